@@ -0,0 +1,297 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+)
+
+const (
+	// jobPollInterval is the interval in which pending async jobs are polled
+	jobPollInterval = 2 * time.Second
+
+	// jobListMargin is subtracted from the submission time of the oldest
+	// pending job when listing jobs, to allow for a clock difference with
+	// the API. Jobs that are not listed are queried one by one.
+	jobListMargin = 5 * time.Minute
+
+	// jobStartDateFormat is the date format of the startdate of listAsyncJobs
+	jobStartDateFormat = "2006-01-02T15:04:05-0700"
+
+	// The async job statuses as returned by the API
+	jobStatusPending   = 0
+	jobStatusSucceeded = 1
+	jobStatusFailed    = 2
+)
+
+// jobPollers holds the job poller of every client created by Config.NewClient
+var jobPollers sync.Map
+
+// jobSlots limits the number of async jobs that are submitted at the same
+// time. A slot is only taken while submitting a job and not while waiting
+// for it, so any number of submitted jobs can be pending and polled
+// together. A nil jobSlots doesn't limit anything.
+type jobSlots chan struct{}
+
+func (s jobSlots) take() {
+	if s != nil {
+		s <- struct{}{}
+	}
+}
+
+func (s jobSlots) release() {
+	if s != nil {
+		<-s
+	}
+}
+
+// jobAPI is the part of the API that is used to poll async jobs.
+type jobAPI interface {
+	// listJobs returns the status of the jobs started since the given time
+	listJobs(since time.Time) (map[string]int, error)
+
+	// queryJob returns the status of a single job
+	queryJob(id string) (int, error)
+
+	// jobError returns the error of a failed job
+	jobError(id string) error
+}
+
+// clientJobAPI polls async jobs using a client.
+type clientJobAPI struct {
+	cs      *cloudstack.CloudStackClient
+	timeout int64
+}
+
+func (a *clientJobAPI) listJobs(since time.Time) (map[string]int, error) {
+	p := a.cs.Asyncjob.NewListAsyncJobsParams()
+	p.SetStartdate(since.Format(jobStartDateFormat))
+
+	l, err := a.cs.Asyncjob.ListAsyncJobs(p)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make(map[string]int, len(l.AsyncJobs))
+	for _, job := range l.AsyncJobs {
+		status[job.Jobid] = job.Jobstatus
+	}
+
+	return status, nil
+}
+
+func (a *clientJobAPI) queryJob(id string) (int, error) {
+	r, err := a.cs.Asyncjob.QueryAsyncJobResult(a.cs.Asyncjob.NewQueryAsyncJobResultParams(id))
+	if err != nil {
+		return 0, err
+	}
+	return r.Jobstatus, nil
+}
+
+func (a *clientJobAPI) jobError(id string) error {
+	// The job is already finished, so this returns the job error without
+	// waiting
+	_, err := a.cs.GetAsyncJobResult(id, a.timeout)
+	if err == nil {
+		err = fmt.Errorf("Async job %s failed", id)
+	}
+	return err
+}
+
+// jobPoller submits async jobs without waiting for them, and polls all
+// pending jobs together. Instead of polling every job on its own, the status
+// of all pending jobs is queried in a single listAsyncJobs call.
+type jobPoller struct {
+	// jcs is used to submit the jobs, api to poll them. Without an api the
+	// jobs are already finished when they are submitted.
+	jcs *cloudstack.CloudStackClient
+	api jobAPI
+
+	// interval is the interval in which the pending jobs are polled
+	interval time.Duration
+
+	// timeout is the time after which a pending job is failed
+	timeout time.Duration
+
+	mu      sync.Mutex
+	jobs    map[string]*pendingJob
+	running bool
+}
+
+type pendingJob struct {
+	done      chan error
+	submitted time.Time
+	expires   time.Time
+}
+
+func newJobPoller(jcs *cloudstack.CloudStackClient, api jobAPI, timeout time.Duration) *jobPoller {
+	return &jobPoller{
+		jcs:      jcs,
+		api:      api,
+		interval: jobPollInterval,
+		timeout:  timeout,
+		jobs:     make(map[string]*pendingJob),
+	}
+}
+
+// registerJobPoller registers a job poller for a client, which submits jobs
+// using jcs.
+func registerJobPoller(cs, jcs *cloudstack.CloudStackClient, timeout int64) {
+	api := &clientJobAPI{cs: cs, timeout: timeout}
+	jobPollers.Store(cs, newJobPoller(jcs, api, time.Duration(timeout)*time.Second))
+}
+
+// jobs returns the job poller of a client. Clients that are not created by
+// Config.NewClient get a poller that submits jobs with the client itself,
+// in which case the jobs are already finished when they are submitted.
+func jobs(cs *cloudstack.CloudStackClient) *jobPoller {
+	if p, ok := jobPollers.Load(cs); ok {
+		return p.(*jobPoller)
+	}
+
+	p, _ := jobPollers.LoadOrStore(cs, newJobPoller(cs, nil, 0))
+
+	return p.(*jobPoller)
+}
+
+// client returns the client to submit async jobs with, the returned
+// responses only contain the ID of the job and of the affected resource.
+func (p *jobPoller) client() *cloudstack.CloudStackClient {
+	return p.jcs
+}
+
+// wait waits until the job with the given ID is finished and returns an
+// error if the job failed or didn't finish in time.
+func (p *jobPoller) wait(jobID string) error {
+	// Jobs submitted with a client that waits for them are already finished
+	if jobID == "" || p.api == nil {
+		return nil
+	}
+
+	now := time.Now()
+	job := &pendingJob{
+		done:      make(chan error, 1),
+		submitted: now,
+		expires:   now.Add(p.timeout),
+	}
+
+	p.mu.Lock()
+	p.jobs[jobID] = job
+	if !p.running {
+		p.running = true
+		go p.run()
+	}
+	p.mu.Unlock()
+
+	return <-job.done
+}
+
+// result returns the result of a finished job, for responses that only
+// contain the ID of the job.
+func (p *jobPoller) result(jobID string) (json.RawMessage, error) {
+	// The job is already finished, so this doesn't wait for it
+	return p.jcs.GetAsyncJobResult(jobID, int64(p.timeout/time.Second))
+}
+
+// run polls the pending jobs until there are none left.
+func (p *jobPoller) run() {
+	for {
+		time.Sleep(p.interval)
+
+		p.mu.Lock()
+		if len(p.jobs) == 0 {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		ids := make([]string, 0, len(p.jobs))
+		oldest := time.Now()
+		for id, job := range p.jobs {
+			ids = append(ids, id)
+			if job.submitted.Before(oldest) {
+				oldest = job.submitted
+			}
+		}
+		p.mu.Unlock()
+
+		finished := p.poll(ids, oldest)
+
+		p.mu.Lock()
+		for _, id := range ids {
+			job := p.jobs[id]
+
+			err, ok := finished[id]
+			if !ok {
+				if time.Now().Before(job.expires) {
+					continue
+				}
+				err = cloudstack.AsyncTimeoutErr
+			}
+			job.done <- err
+			delete(p.jobs, id)
+		}
+		p.mu.Unlock()
+	}
+}
+
+// poll returns the result of the given jobs that are finished. The oldest
+// job was submitted at the given time.
+func (p *jobPoller) poll(ids []string, oldest time.Time) map[string]error {
+	var status map[string]int
+
+	// With more than one job pending, list the recent jobs to get their
+	// status in a single call instead of querying them one by one
+	if len(ids) > 1 {
+		var err error
+		status, err = p.api.listJobs(oldest.Add(-jobListMargin))
+		if err != nil {
+			log.Printf("[WARN] Failed to list async jobs: %s", err)
+		}
+	}
+
+	finished := make(map[string]error)
+	for _, id := range ids {
+		s, ok := status[id]
+		if !ok {
+			var err error
+			s, err = p.api.queryJob(id)
+			if err != nil {
+				// Try again with the next poll
+				log.Printf("[WARN] Failed to query async job %s: %s", id, err)
+				continue
+			}
+		}
+
+		switch s {
+		case jobStatusSucceeded:
+			finished[id] = nil
+		case jobStatusFailed:
+			finished[id] = p.api.jobError(id)
+		}
+	}
+
+	return finished
+}
@@ -0,0 +1,353 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+)
+
+// testJobAPI is a jobAPI of which the job statuses are set by the test.
+// Jobs that are not in status are pending.
+type testJobAPI struct {
+	mu      sync.Mutex
+	status  map[string]int
+	listErr error
+	listed  []string
+	queried []string
+	since   []time.Time
+
+	// finishAfter finishes all pending jobs after the given number of polls
+	finishAfter int
+	polls       int
+}
+
+func (a *testJobAPI) listJobs(since time.Time) (map[string]int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.poll()
+	a.since = append(a.since, since)
+	if a.listErr != nil {
+		return nil, a.listErr
+	}
+
+	status := make(map[string]int)
+	for _, id := range a.listed {
+		if s, ok := a.status[id]; ok {
+			status[id] = s
+		} else {
+			status[id] = jobStatusPending
+		}
+	}
+
+	return status, nil
+}
+
+func (a *testJobAPI) queryJob(id string) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.queried = append(a.queried, id)
+	if s, ok := a.status[id]; ok {
+		return s, nil
+	}
+	return jobStatusPending, nil
+}
+
+func (a *testJobAPI) jobError(id string) error {
+	return fmt.Errorf("job %s failed", id)
+}
+
+// poll finishes all jobs once finishAfter polls are done.
+func (a *testJobAPI) poll() {
+	a.polls++
+	if a.finishAfter > 0 && a.polls >= a.finishAfter {
+		for _, id := range a.listed {
+			if _, ok := a.status[id]; !ok {
+				a.status[id] = jobStatusSucceeded
+			}
+		}
+	}
+}
+
+func TestJobPollerWait(t *testing.T) {
+	tests := []struct {
+		name        string
+		jobs        []string
+		status      map[string]int
+		listed      []string
+		listErr     error
+		finishAfter int
+		timeout     time.Duration
+		expected    map[string]string
+		queried     bool
+	}{
+		{
+			name:     "single job",
+			jobs:     []string{"a"},
+			status:   map[string]int{"a": jobStatusSucceeded},
+			expected: map[string]string{"a": ""},
+			queried:  true,
+		},
+		{
+			name:     "listed jobs",
+			jobs:     []string{"a", "b", "c"},
+			status:   map[string]int{"a": jobStatusSucceeded, "b": jobStatusSucceeded, "c": jobStatusSucceeded},
+			listed:   []string{"a", "b", "c"},
+			expected: map[string]string{"a": "", "b": "", "c": ""},
+		},
+		{
+			name:     "failed job",
+			jobs:     []string{"a", "b"},
+			status:   map[string]int{"a": jobStatusSucceeded, "b": jobStatusFailed},
+			listed:   []string{"a", "b"},
+			expected: map[string]string{"a": "", "b": "job b failed"},
+		},
+		{
+			name:     "job not listed",
+			jobs:     []string{"a", "b"},
+			status:   map[string]int{"a": jobStatusSucceeded, "b": jobStatusSucceeded},
+			listed:   []string{"a"},
+			expected: map[string]string{"a": "", "b": ""},
+			queried:  true,
+		},
+		{
+			name:     "listing fails",
+			jobs:     []string{"a", "b"},
+			status:   map[string]int{"a": jobStatusSucceeded, "b": jobStatusFailed},
+			listErr:  fmt.Errorf("listAsyncJobs failed"),
+			expected: map[string]string{"a": "", "b": "job b failed"},
+			queried:  true,
+		},
+		{
+			name:        "pending jobs",
+			jobs:        []string{"a", "b", "c"},
+			status:      map[string]int{},
+			listed:      []string{"a", "b", "c"},
+			finishAfter: 3,
+			expected:    map[string]string{"a": "", "b": "", "c": ""},
+		},
+		{
+			name:     "timeout",
+			jobs:     []string{"a", "b"},
+			status:   map[string]int{"a": jobStatusSucceeded},
+			listed:   []string{"a", "b"},
+			timeout:  50 * time.Millisecond,
+			expected: map[string]string{"a": "", "b": cloudstack.AsyncTimeoutErr.Error()},
+			// Once b is the only pending job, it's queried on its own
+			queried: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &testJobAPI{
+				status:      tt.status,
+				listed:      tt.listed,
+				listErr:     tt.listErr,
+				finishAfter: tt.finishAfter,
+			}
+
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = time.Minute
+			}
+
+			p := newJobPoller(nil, api, timeout)
+			p.interval = 10 * time.Millisecond
+
+			results := testWaitForJobs(p, tt.jobs)
+
+			for id, expected := range tt.expected {
+				if results[id] != expected {
+					t.Errorf("job %s: got error %q, expected %q", id, results[id], expected)
+				}
+			}
+
+			if queried := len(api.queried) > 0; queried != tt.queried {
+				t.Errorf("jobs queried one by one: %t, expected %t", queried, tt.queried)
+			}
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if len(p.jobs) != 0 {
+				t.Errorf("%d jobs are still pending", len(p.jobs))
+			}
+		})
+	}
+}
+
+func TestJobPollerStartDate(t *testing.T) {
+	api := &testJobAPI{
+		status: map[string]int{"a": jobStatusSucceeded, "b": jobStatusSucceeded},
+		listed: []string{"a", "b"},
+	}
+
+	p := newJobPoller(nil, api, time.Minute)
+	p.interval = 10 * time.Millisecond
+
+	before := time.Now()
+	testWaitForJobs(p, []string{"a", "b"})
+
+	if len(api.since) == 0 {
+		t.Fatalf("jobs were not listed")
+	}
+
+	since := api.since[0].Add(jobListMargin)
+	if since.Before(before) || since.After(time.Now()) {
+		t.Errorf("jobs listed since %s, expected the submission of the oldest job minus %s",
+			api.since[0], jobListMargin)
+	}
+}
+
+func TestJobPollerConcurrentWaits(t *testing.T) {
+	const count = 50
+
+	api := &testJobAPI{status: make(map[string]int)}
+
+	var ids []string
+	for i := 0; i < count; i++ {
+		id := fmt.Sprintf("job-%d", i)
+		ids = append(ids, id)
+		api.listed = append(api.listed, id)
+
+		// Fail every fifth job
+		if i%5 == 0 {
+			api.status[id] = jobStatusFailed
+		}
+	}
+	api.finishAfter = 2
+
+	p := newJobPoller(nil, api, time.Minute)
+	p.interval = 10 * time.Millisecond
+
+	results := testWaitForJobs(p, ids)
+
+	for i, id := range ids {
+		expected := ""
+		if i%5 == 0 {
+			expected = fmt.Sprintf("job %s failed", id)
+		}
+		if results[id] != expected {
+			t.Errorf("job %s: got error %q, expected %q", id, results[id], expected)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running && len(p.jobs) != 0 {
+		t.Errorf("poller is still running with %d pending jobs", len(p.jobs))
+	}
+}
+
+func TestJobPollerWithoutAPI(t *testing.T) {
+	p := newJobPoller(nil, nil, time.Minute)
+
+	if err := p.wait("a"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running || len(p.jobs) != 0 {
+		t.Errorf("jobs of a client that waits for them must not be polled")
+	}
+}
+
+func TestJobSlots(t *testing.T) {
+	// A nil jobSlots doesn't block
+	var none jobSlots
+	none.take()
+	none.release()
+
+	sem := make(jobSlots, 1)
+	sem.take()
+
+	taken := make(chan struct{})
+	go func() {
+		sem.take()
+		close(taken)
+	}()
+
+	select {
+	case <-taken:
+		t.Fatalf("took a slot while all slots are in use")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	sem.release()
+
+	select {
+	case <-taken:
+	case <-time.After(time.Second):
+		t.Fatalf("slot wasn't taken after it was released")
+	}
+}
+
+// testWaitForJobs waits for all jobs concurrently and returns their errors.
+// The poller is only started once all jobs are pending, so the first poll
+// always sees all jobs.
+func testWaitForJobs(p *jobPoller, ids []string) map[string]string {
+	var mu sync.Mutex
+	results := make(map[string]string, len(ids))
+
+	var wg sync.WaitGroup
+	wg.Add(len(ids))
+
+	p.mu.Lock()
+	p.running = true
+	p.mu.Unlock()
+
+	for _, id := range ids {
+		go func(id string) {
+			defer wg.Done()
+
+			var result string
+			if err := p.wait(id); err != nil {
+				result = err.Error()
+			}
+
+			mu.Lock()
+			results[id] = result
+			mu.Unlock()
+		}(id)
+	}
+
+	for {
+		p.mu.Lock()
+		pending := len(p.jobs)
+		p.mu.Unlock()
+
+		if pending == len(ids) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	go p.run()
+
+	wg.Wait()
+
+	return results
+}
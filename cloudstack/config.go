@@ -29,12 +29,27 @@ type Config struct {
 	SecretKey   string
 	HTTPGETOnly bool
 	Timeout     int64
+	PortAliases map[string]string
 }
 
 // NewClient returns a new CloudStack client.
 func (c *Config) NewClient() (*cloudstack.CloudStackClient, error) {
+	aliases, err := newPortAliases(c.PortAliases)
+	if err != nil {
+		return nil, err
+	}
+
 	cs := cloudstack.NewAsyncClient(c.APIURL, c.APIKey, c.SecretKey, false)
 	cs.HTTPGETOnly = c.HTTPGETOnly
 	cs.AsyncTimeout(c.Timeout)
+
+	// Rule resources submit their async jobs without waiting for them and
+	// poll all pending jobs together
+	jcs := cloudstack.NewClient(c.APIURL, c.APIKey, c.SecretKey, false)
+	jcs.HTTPGETOnly = c.HTTPGETOnly
+	registerJobPoller(cs, jcs, c.Timeout)
+
+	clientPortAliases.Store(cs, aliases)
+
 	return cs, nil
 }
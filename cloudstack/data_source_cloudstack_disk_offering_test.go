@@ -0,0 +1,66 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDiskOfferingDataSource_basic(t *testing.T) {
+	resourceName := "cloudstack_disk_offering.disk-off-resource"
+	datasourceName := "data.cloudstack_disk_offering.disk-off-data-source"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testDiskOfferingDataSourceConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(datasourceName, "name", resourceName, "name"),
+					resource.TestCheckResourceAttrPair(datasourceName, "display_text", resourceName, "display_text"),
+					resource.TestCheckResourceAttrPair(datasourceName, "disk_size", resourceName, "disk_size"),
+					resource.TestCheckResourceAttrPair(datasourceName, "storage_tags", resourceName, "storage_tags"),
+				),
+			},
+		},
+	})
+}
+
+const testDiskOfferingDataSourceConfig_basic = `
+resource "cloudstack_disk_offering" "disk-off-resource" {
+  name         = "TestDiskOfferingDisplay01"
+  display_text = "TestDiskOfferingDisplay01"
+  disk_size    = 5
+  storage_tags = "ssd"
+}
+
+data "cloudstack_disk_offering" "disk-off-data-source" {
+  filter {
+    name  = "name"
+    value = "TestDiskOfferingDisplay01"
+  }
+  depends_on = [
+    cloudstack_disk_offering.disk-off-resource
+  ]
+}
+`
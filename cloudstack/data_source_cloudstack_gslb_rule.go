@@ -0,0 +1,156 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCloudstackGSLBRule() *schema.Resource {
+	return &schema.Resource{
+		Read: datasourceCloudStackGSLBRuleRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			"region_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+
+			//Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"domain_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"service_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"method": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"persistence": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"loadbalancer_rule_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func datasourceCloudStackGSLBRuleRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+	p := cs.LoadBalancer.NewListGlobalLoadBalancerRulesParams()
+
+	if regionid, ok := d.GetOk("region_id"); ok {
+		p.SetRegionid(regionid.(int))
+	}
+
+	csGSLBRules, err := cs.LoadBalancer.ListGlobalLoadBalancerRules(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list GSLB rules: %s", err)
+	}
+
+	filters := d.Get("filter")
+	var gslbRules []*cloudstack.GlobalLoadBalancerRule
+
+	for _, r := range csGSLBRules.GlobalLoadBalancerRules {
+		match, err := applyGSLBRuleFilters(r, filters.(*schema.Set))
+		if err != nil {
+			return err
+		}
+		if match {
+			gslbRules = append(gslbRules, r)
+		}
+	}
+
+	if len(gslbRules) == 0 {
+		return fmt.Errorf("No GSLB rule is matching with the specified regex")
+	}
+	// GSLB rules have no creation date, so the filters need to be specific
+	if len(gslbRules) > 1 {
+		return fmt.Errorf("More than one GSLB rule is matching with the specified regex")
+	}
+	log.Printf("[DEBUG] Selected GSLB rule: %s\n", gslbRules[0].Name)
+
+	return gslbRuleDescriptionAttributes(d, gslbRules[0])
+}
+
+func gslbRuleDescriptionAttributes(d *schema.ResourceData, gslbRule *cloudstack.GlobalLoadBalancerRule) error {
+	d.SetId(gslbRule.Id)
+	d.Set("name", gslbRule.Name)
+	d.Set("description", gslbRule.Description)
+	d.Set("domain_name", gslbRule.Gslbdomainname)
+	d.Set("service_type", gslbRule.Gslbservicetype)
+	d.Set("method", gslbRule.Gslblbmethod)
+	d.Set("persistence", gslbRule.Gslbstickysessionmethodname)
+	d.Set("region_id", gslbRule.Regionid)
+
+	var lbruleids []string
+	for _, lb := range gslbRule.Loadbalancerrule {
+		lbruleids = append(lbruleids, lb.Id)
+	}
+	d.Set("loadbalancer_rule_ids", lbruleids)
+
+	return nil
+}
+
+func applyGSLBRuleFilters(gslbRule *cloudstack.GlobalLoadBalancerRule, filters *schema.Set) (bool, error) {
+	var gslbRuleJSON map[string]interface{}
+	k, _ := json.Marshal(gslbRule)
+	err := json.Unmarshal(k, &gslbRuleJSON)
+	if err != nil {
+		return false, err
+	}
+
+	for _, f := range filters.List() {
+		m := f.(map[string]interface{})
+		r, err := regexp.Compile(m["value"].(string))
+		if err != nil {
+			return false, fmt.Errorf("Invalid regex: %s", err)
+		}
+		updatedName := strings.ReplaceAll(m["name"].(string), "_", "")
+		gslbRuleField := fmt.Sprintf("%v", gslbRuleJSON[updatedName])
+		if !r.MatchString(gslbRuleField) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,102 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCloudstackNetworkACLRules() *schema.Resource {
+	return &schema.Resource{
+		Read: datasourceCloudStackNetworkACLRulesRead,
+		Schema: map[string]*schema.Schema{
+			"acl_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the network ACL to export the rules of.",
+			},
+
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name or ID of the project the network ACL belongs to.",
+			},
+
+			//Computed values
+			"rules_csv": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The rules of the network ACL as a CSV document.",
+			},
+
+			"rules_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The rules of the network ACL as a JSON document.",
+			},
+		},
+	}
+}
+
+func datasourceCloudStackNetworkACLRulesRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+	aclID := d.Get("acl_id").(string)
+
+	log.Printf("[DEBUG] Exporting the rules of network ACL %s", aclID)
+
+	p := cs.NetworkACL.NewListNetworkACLsParams()
+	p.SetAclid(aclID)
+	p.SetListall(true)
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	l, err := cs.NetworkACL.ListNetworkACLs(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list the rules of network ACL %s: %s", aclID, err)
+	}
+
+	rules := make([]map[string]interface{}, 0, l.Count)
+	for _, r := range l.NetworkACLs {
+		rules = append(rules, buildRuleFromAPI(r))
+	}
+
+	rulesCSV, err := formatACLRulesCSV(rules)
+	if err != nil {
+		return fmt.Errorf("Failed to export the rules of network ACL %s as CSV: %s", aclID, err)
+	}
+
+	rulesJSON, err := formatACLRulesJSON(rules)
+	if err != nil {
+		return fmt.Errorf("Failed to export the rules of network ACL %s as JSON: %s", aclID, err)
+	}
+
+	d.SetId(aclID)
+	d.Set("rules_csv", rulesCSV)
+	d.Set("rules_json", rulesJSON)
+
+	return nil
+}
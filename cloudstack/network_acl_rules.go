@@ -0,0 +1,326 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// aclRuleColumns are the columns of an ACL rules document, in the same order
+// as the CloudStack UI export.
+var aclRuleColumns = []string{
+	"number", "action", "cidrlist", "protocol", "startport", "endport",
+	"icmptype", "icmpcode", "traffictype", "reason",
+}
+
+// aclRuleColumnAliases maps alternative column names to the column names
+// used by CloudStack.
+var aclRuleColumnAliases = map[string]string{
+	"rulenumber":  "number",
+	"cidr":        "cidrlist",
+	"description": "reason",
+}
+
+// aclRuleDocument is a single rule of an ACL rules JSON document.
+type aclRuleDocument struct {
+	Number      int    `json:"number"`
+	Action      string `json:"action"`
+	Cidrlist    string `json:"cidrlist"`
+	Protocol    string `json:"protocol"`
+	Startport   string `json:"startport,omitempty"`
+	Endport     string `json:"endport,omitempty"`
+	Icmptype    *int   `json:"icmptype,omitempty"`
+	Icmpcode    *int   `json:"icmpcode,omitempty"`
+	Traffictype string `json:"traffictype"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// parseACLRulesCSV parses an ACL rules CSV document with a header row into
+// rules with the same fields as the rule blocks of the ACL ruleset.
+func parseACLRulesCSV(document string) ([]map[string]interface{}, error) {
+	r := csv.NewReader(strings.NewReader(document))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Error reading the header of the ACL rules CSV: %s", err)
+	}
+
+	for i, column := range header {
+		header[i] = normalizeACLRuleColumn(column)
+	}
+
+	var records []map[string]string
+	for line := 2; ; line++ {
+		fields, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error reading line %d of the ACL rules CSV: %s", line, err)
+		}
+
+		record := make(map[string]string, len(fields))
+		for i, field := range fields {
+			if i < len(header) {
+				record[header[i]] = strings.TrimSpace(field)
+			}
+		}
+		records = append(records, record)
+	}
+
+	return aclRulesFromRecords(records)
+}
+
+// parseACLRulesJSON parses an ACL rules JSON document, which is either a
+// list of rules or an object with a list of rules in its "rules" key.
+func parseACLRulesJSON(document string) ([]map[string]interface{}, error) {
+	var items []map[string]interface{}
+	if err := json.Unmarshal([]byte(document), &items); err != nil {
+		var wrapped struct {
+			Rules []map[string]interface{} `json:"rules"`
+		}
+		if e := json.Unmarshal([]byte(document), &wrapped); e != nil {
+			return nil, fmt.Errorf("Error parsing the ACL rules JSON: %s", err)
+		}
+		items = wrapped.Rules
+	}
+
+	records := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		record := make(map[string]string, len(item))
+		for k, v := range item {
+			switch v := v.(type) {
+			case nil:
+				continue
+			case float64:
+				record[normalizeACLRuleColumn(k)] = strconv.FormatFloat(v, 'f', -1, 64)
+			case []interface{}:
+				var values []string
+				for _, value := range v {
+					values = append(values, fmt.Sprint(value))
+				}
+				record[normalizeACLRuleColumn(k)] = strings.Join(values, ",")
+			default:
+				record[normalizeACLRuleColumn(k)] = strings.TrimSpace(fmt.Sprint(v))
+			}
+		}
+		records = append(records, record)
+	}
+
+	return aclRulesFromRecords(records)
+}
+
+func normalizeACLRuleColumn(column string) string {
+	column = strings.ToLower(strings.TrimSpace(column))
+	column = strings.NewReplacer("_", "", "-", "", " ", "").Replace(column)
+
+	if alias, ok := aclRuleColumnAliases[column]; ok {
+		return alias
+	}
+
+	return column
+}
+
+func aclRulesFromRecords(records []map[string]string) ([]map[string]interface{}, error) {
+	numbers := make(map[int]bool, len(records))
+
+	var rules []map[string]interface{}
+	for i, record := range records {
+		number, err := strconv.Atoi(record["number"])
+		if err != nil {
+			return nil, fmt.Errorf("Rule %d has an invalid rule number %q", i+1, record["number"])
+		}
+		if numbers[number] {
+			return nil, fmt.Errorf("Rule number %d is used more than once", number)
+		}
+		numbers[number] = true
+
+		cidrs := &schema.Set{F: schema.HashString}
+		for _, cidr := range strings.FieldsFunc(record["cidrlist"], func(r rune) bool {
+			return r == ',' || r == ';' || r == ' '
+		}) {
+			cidrs.Add(cidr)
+		}
+		if cidrs.Len() == 0 {
+			return nil, fmt.Errorf("Rule %d has no cidrlist", number)
+		}
+
+		rule := map[string]interface{}{
+			"rule_number":  number,
+			"action":       defaultString(strings.ToLower(record["action"]), "allow"),
+			"cidr_list":    cidrs,
+			"protocol":     strings.ToLower(record["protocol"]),
+			"icmp_type":    -1,
+			"icmp_code":    -1,
+			"port":         "",
+			"traffic_type": defaultString(strings.ToLower(record["traffictype"]), "ingress"),
+			"description":  record["reason"],
+			"uuid":         "",
+		}
+
+		protocol, _ := aclProtocol(rule["protocol"].(string))
+
+		switch protocol {
+		case "icmp":
+			for key, column := range map[string]string{"icmp_type": "icmptype", "icmp_code": "icmpcode"} {
+				if record[column] == "" {
+					continue
+				}
+				value, err := strconv.Atoi(record[column])
+				if err != nil {
+					return nil, fmt.Errorf("Rule %d has an invalid %s %q", number, column, record[column])
+				}
+				rule[key] = value
+			}
+		case "tcp", "udp":
+			start, end := record["startport"], record["endport"]
+			if start != "" && end != "" && end != start {
+				rule["port"] = fmt.Sprintf("%s-%s", start, end)
+			} else if start != "" {
+				rule["port"] = start
+			}
+		}
+
+		// The ports are built from the port columns, so port aliases don't apply
+		if err := verifyACLRuleParams(nil, nil, rule); err != nil {
+			return nil, fmt.Errorf("Rule %d is invalid: %s", number, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func defaultString(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// aclRuleDocuments converts rules with the same fields as the rule blocks of
+// the ACL ruleset into documents sorted by rule number.
+func aclRuleDocuments(pa portAliases, rules []map[string]interface{}) []aclRuleDocument {
+	docs := make([]aclRuleDocument, 0, len(rules))
+	for _, rule := range rules {
+		var cidrs []string
+		for _, cidr := range rule["cidr_list"].(*schema.Set).List() {
+			cidrs = append(cidrs, cidr.(string))
+		}
+		sort.Strings(cidrs)
+
+		doc := aclRuleDocument{
+			Number:      rule["rule_number"].(int),
+			Action:      rule["action"].(string),
+			Cidrlist:    strings.Join(cidrs, ","),
+			Protocol:    rule["protocol"].(string),
+			Traffictype: rule["traffic_type"].(string),
+			Reason:      rule["description"].(string),
+		}
+
+		// Protocols given by name or number are exported the way the API uses them
+		if protocol, err := aclProtocol(doc.Protocol); err == nil {
+			doc.Protocol = protocol
+		}
+
+		switch doc.Protocol {
+		case "icmp":
+			icmpType := rule["icmp_type"].(int)
+			icmpCode := rule["icmp_code"].(int)
+			doc.Icmptype = &icmpType
+			doc.Icmpcode = &icmpCode
+		case "tcp", "udp":
+			if port := rule["port"].(string); port != "" {
+				if startPort, endPort, err := pa.parsePortRange(port); err == nil {
+					doc.Startport = strconv.Itoa(startPort)
+					doc.Endport = strconv.Itoa(endPort)
+				}
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Number < docs[j].Number
+	})
+
+	return docs
+}
+
+// formatACLRulesCSV formats rules read from the API as a CSV document with a
+// header row.
+func formatACLRulesCSV(rules []map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(aclRuleColumns); err != nil {
+		return "", err
+	}
+
+	for _, doc := range aclRuleDocuments(nil, rules) {
+		icmpType, icmpCode := "", ""
+		if doc.Icmptype != nil {
+			icmpType = strconv.Itoa(*doc.Icmptype)
+		}
+		if doc.Icmpcode != nil {
+			icmpCode = strconv.Itoa(*doc.Icmpcode)
+		}
+
+		if err := w.Write([]string{
+			strconv.Itoa(doc.Number), doc.Action, doc.Cidrlist, doc.Protocol,
+			doc.Startport, doc.Endport, icmpType, icmpCode, doc.Traffictype, doc.Reason,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// formatACLRulesJSON formats rules read from the API as a JSON document.
+func formatACLRulesJSON(rules []map[string]interface{}) (string, error) {
+	b, err := json.MarshalIndent(aclRuleDocuments(nil, rules), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,216 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseACLRulesCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		document  string
+		expected  map[int]map[string]interface{}
+		expectErr string
+	}{
+		{
+			name: "CloudStack UI export",
+			document: `id,number,action,cidrlist,protocol,startport,endport,icmptype,icmpcode,traffictype,reason
+2f1c,10,Allow,"10.0.0.0/8,192.168.0.0/16",tcp,22,22,,,Ingress,Allow SSH
+3a2d,20,Deny,0.0.0.0/0,udp,1000,2000,,,Egress,
+4b3e,30,Allow,0.0.0.0/0,icmp,,,8,0,Ingress,Allow ping
+`,
+			expected: map[int]map[string]interface{}{
+				10: {"action": "allow", "protocol": "tcp", "port": "22", "traffic_type": "ingress", "description": "Allow SSH", "cidrs": 2},
+				20: {"action": "deny", "protocol": "udp", "port": "1000-2000", "traffic_type": "egress", "description": "", "cidrs": 1},
+				30: {"action": "allow", "protocol": "icmp", "icmp_type": 8, "icmp_code": 0, "port": "", "cidrs": 1},
+			},
+		},
+		{
+			name: "column aliases and defaults",
+			document: `rule_number,cidr_list,protocol,description
+100,10.1.0.0/16,all,Allow all
+`,
+			expected: map[int]map[string]interface{}{
+				100: {"action": "allow", "protocol": "all", "traffic_type": "ingress", "description": "Allow all", "icmp_type": -1, "cidrs": 1},
+			},
+		},
+		{
+			name:     "empty document",
+			document: "",
+			expected: map[int]map[string]interface{}{},
+		},
+		{
+			name: "duplicate rule number",
+			document: `number,cidrlist,protocol
+10,0.0.0.0/0,tcp
+10,0.0.0.0/0,udp
+`,
+			expectErr: "Rule number 10 is used more than once",
+		},
+		{
+			name: "invalid protocol",
+			document: `number,cidrlist,protocol
+10,0.0.0.0/0,bogus
+`,
+			expectErr: "is not a valid protocol",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := parseACLRulesCSV(tt.document)
+			testCheckACLRules(t, rules, err, tt.expected, tt.expectErr)
+		})
+	}
+}
+
+func TestParseACLRulesJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		document  string
+		expected  map[int]map[string]interface{}
+		expectErr string
+	}{
+		{
+			name: "list of rules",
+			document: `[
+  {"number": 10, "action": "allow", "cidrlist": "10.0.0.0/8", "protocol": "tcp", "startport": "443", "endport": "443", "traffictype": "ingress", "reason": "Allow HTTPS"},
+  {"number": "20", "action": "deny", "cidrlist": ["0.0.0.0/0"], "protocol": "all", "traffictype": "egress"}
+]`,
+			expected: map[int]map[string]interface{}{
+				10: {"action": "allow", "protocol": "tcp", "port": "443", "description": "Allow HTTPS", "cidrs": 1},
+				20: {"action": "deny", "protocol": "all", "traffic_type": "egress", "cidrs": 1},
+			},
+		},
+		{
+			name:     "object with a list of rules",
+			document: `{"rules": [{"rule_number": 5, "cidr_list": "10.0.0.0/8", "protocol": "icmp", "icmp_type": -1, "icmp_code": -1}]}`,
+			expected: map[int]map[string]interface{}{
+				5: {"protocol": "icmp", "icmp_type": -1, "icmp_code": -1, "cidrs": 1},
+			},
+		},
+		{
+			name:      "invalid document",
+			document:  `{"rules": `,
+			expectErr: "Error parsing the ACL rules JSON",
+		},
+		{
+			name:      "missing cidrlist",
+			document:  `[{"number": 10, "protocol": "tcp"}]`,
+			expectErr: "Rule 10 has no cidrlist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := parseACLRulesJSON(tt.document)
+			testCheckACLRules(t, rules, err, tt.expected, tt.expectErr)
+		})
+	}
+}
+
+func TestFormatACLRules(t *testing.T) {
+	document := `number,action,cidrlist,protocol,startport,endport,icmptype,icmpcode,traffictype,reason
+20,deny,0.0.0.0/0,udp,1000,2000,,,egress,
+10,allow,"10.0.0.0/8,192.168.0.0/16",tcp,22,22,,,ingress,Allow SSH
+30,allow,0.0.0.0/0,icmp,,,8,0,ingress,Allow ping
+`
+	rules, err := parseACLRulesCSV(document)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	rulesCSV, err := formatACLRulesCSV(rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `number,action,cidrlist,protocol,startport,endport,icmptype,icmpcode,traffictype,reason
+10,allow,"10.0.0.0/8,192.168.0.0/16",tcp,22,22,,,ingress,Allow SSH
+20,deny,0.0.0.0/0,udp,1000,2000,,,egress,
+30,allow,0.0.0.0/0,icmp,,,8,0,ingress,Allow ping
+`
+	if rulesCSV != expected {
+		t.Fatalf("expected CSV:\n%s\ngot:\n%s", expected, rulesCSV)
+	}
+
+	// Exporting and importing the rules as JSON should result in the same rules
+	rulesJSON, err := formatACLRulesJSON(rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	parsed, err := parseACLRulesJSON(rulesJSON)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	roundTrip, err := formatACLRulesCSV(parsed)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if roundTrip != expected {
+		t.Fatalf("expected CSV after JSON round trip:\n%s\ngot:\n%s", expected, roundTrip)
+	}
+}
+
+func testCheckACLRules(t *testing.T, rules []map[string]interface{}, err error, expected map[int]map[string]interface{}, expectErr string) {
+	t.Helper()
+
+	if expectErr != "" {
+		if err == nil || !strings.Contains(err.Error(), expectErr) {
+			t.Fatalf("expected error containing %q, got: %v", expectErr, err)
+		}
+		return
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %d rules, got %d", len(expected), len(rules))
+	}
+
+	for _, rule := range rules {
+		number := rule["rule_number"].(int)
+
+		fields, ok := expected[number]
+		if !ok {
+			t.Fatalf("unexpected rule %d", number)
+		}
+
+		for k, v := range fields {
+			if k == "cidrs" {
+				if n := rule["cidr_list"].(interface{ Len() int }).Len(); n != v.(int) {
+					t.Errorf("rule %d: expected %d CIDRs, got %d", number, v.(int), n)
+				}
+				continue
+			}
+
+			if rule[k] != v {
+				t.Errorf("rule %d: expected %s to be %v, got %v", number, k, v, rule[k])
+			}
+		}
+	}
+}
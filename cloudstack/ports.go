@@ -0,0 +1,477 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// wellKnownPorts maps the service names that can be used instead of a port
+// number to their port.
+var wellKnownPorts = map[string]string{
+	"ftp-data":      "20",
+	"ftp":           "21",
+	"ssh":           "22",
+	"telnet":        "23",
+	"smtp":          "25",
+	"dns":           "53",
+	"domain":        "53",
+	"http":          "80",
+	"kerberos":      "88",
+	"pop3":          "110",
+	"ntp":           "123",
+	"imap":          "143",
+	"snmp":          "161",
+	"bgp":           "179",
+	"ldap":          "389",
+	"https":         "443",
+	"smb":           "445",
+	"smtps":         "465",
+	"syslog":        "514",
+	"submission":    "587",
+	"ldaps":         "636",
+	"imaps":         "993",
+	"pop3s":         "995",
+	"openvpn":       "1194",
+	"mssql":         "1433",
+	"oracle":        "1521",
+	"nfs":           "2049",
+	"mysql":         "3306",
+	"rdp":           "3389",
+	"postgres":      "5432",
+	"postgresql":    "5432",
+	"amqp":          "5672",
+	"vnc":           "5900",
+	"redis":         "6379",
+	"kubernetes":    "6443",
+	"http-alt":      "8080",
+	"https-alt":     "8443",
+	"elasticsearch": "9200",
+	"memcached":     "11211",
+	"mongodb":       "27017",
+}
+
+// ipProtocols maps the IP protocol names that can be used in ACL rules to
+// their protocol number.
+var ipProtocols = map[string]int{
+	"icmp":      1,
+	"igmp":      2,
+	"ipip":      4,
+	"tcp":       6,
+	"egp":       8,
+	"udp":       17,
+	"ipv6":      41,
+	"gre":       47,
+	"esp":       50,
+	"ah":        51,
+	"ipv6-icmp": 58,
+	"eigrp":     88,
+	"ospf":      89,
+	"pim":       103,
+	"vrrp":      112,
+	"l2tp":      115,
+	"sctp":      132,
+}
+
+// portAliases maps the port aliases configured for a provider to their port.
+// They are used next to, and take precedence over, the built-in service names.
+type portAliases map[string]string
+
+// clientPortAliases holds the port aliases of every client created by
+// Config.NewClient
+var clientPortAliases sync.Map
+
+// newPortAliases validates the configured port aliases and returns them with
+// their names in lower case.
+func newPortAliases(aliases map[string]string) (portAliases, error) {
+	pa := make(portAliases, len(aliases))
+	for name, port := range aliases {
+		if splitPorts.MatchString(name) {
+			return nil, fmt.Errorf("Port alias %q cannot be a port number", name)
+		}
+		if !splitPorts.MatchString(port) {
+			return nil, fmt.Errorf(
+				"Port alias %q has an invalid port %q. Valid options are '80' or '80-90'", name, port)
+		}
+		pa[strings.ToLower(name)] = port
+	}
+
+	return pa, nil
+}
+
+// portAliasesOf returns the port aliases of a client. Clients that are not
+// created by Config.NewClient have no port aliases.
+func portAliasesOf(cs *cloudstack.CloudStackClient) portAliases {
+	if pa, ok := clientPortAliases.Load(cs); ok {
+		return pa.(portAliases)
+	}
+
+	return nil
+}
+
+// parsePortRange parses a port given as "80", "80-90" or as a service name
+// and returns its start and end port.
+func (pa portAliases) parsePortRange(port string) (int, int, error) {
+	value := port
+
+	name := strings.ToLower(port)
+	alias, ok := pa[name]
+	if !ok {
+		alias, ok = wellKnownPorts[name]
+	}
+	if ok {
+		value = alias
+	}
+
+	m := splitPorts.FindStringSubmatch(value)
+	if m == nil {
+		return 0, 0, fmt.Errorf(
+			"%q is not a valid port value. Valid options are '80', '80-90' or a service name like 'https'", port)
+	}
+
+	startPort, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	endPort := startPort
+	if m[2] != "" {
+		endPort, err = strconv.Atoi(m[2])
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if startPort > 65535 || endPort > 65535 || startPort > endPort {
+		return 0, 0, fmt.Errorf("%q is not a valid port range", port)
+	}
+
+	return startPort, endPort, nil
+}
+
+// equivalentPorts reports whether two ports refer to the same port range.
+func (pa portAliases) equivalentPorts(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	aStart, aEnd, err := pa.parsePortRange(a)
+	if err != nil {
+		return false
+	}
+
+	bStart, bEnd, err := pa.parsePortRange(b)
+	if err != nil {
+		return false
+	}
+
+	return aStart == bStart && aEnd == bEnd
+}
+
+// aclProtocol returns the protocol to use in the API for an ACL rule protocol
+// given as a name or a protocol number. TCP, UDP and ICMP are returned by
+// name as they need their ports or ICMP type and code set, other protocols
+// are returned by number.
+func aclProtocol(protocol string) (string, error) {
+	name := strings.ToLower(protocol)
+	if name == "all" {
+		return name, nil
+	}
+
+	number, ok := ipProtocols[name]
+	if !ok {
+		n, err := strconv.Atoi(name)
+		if err != nil {
+			return "", fmt.Errorf(
+				"%q is not a valid protocol. Valid options are 'tcp', 'udp', 'icmp', 'all', "+
+					"a protocol name like 'gre' or a protocol number", protocol)
+		}
+		if n < 0 || n > 255 {
+			return "", fmt.Errorf("protocol number must be between 0 and 255, got: %d", n)
+		}
+		number = n
+	}
+
+	switch number {
+	case 1:
+		return "icmp", nil
+	case 6:
+		return "tcp", nil
+	case 17:
+		return "udp", nil
+	}
+
+	return strconv.Itoa(number), nil
+}
+
+// equivalentACLProtocols reports whether two ACL rule protocols refer to the
+// same protocol.
+func equivalentACLProtocols(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	ap, err := aclProtocol(a)
+	if err != nil {
+		return false
+	}
+
+	bp, err := aclProtocol(b)
+	if err != nil {
+		return false
+	}
+
+	return ap == bp
+}
+
+// portRule is a single port range of a rule. It is used to detect duplicate
+// and overlapping rules before any of them are created.
+type portRule struct {
+	// scope describes everything, other than the sources and ports, that
+	// has to be the same for two rules to overlap, e.g. "ingress tcp"
+	scope string
+
+	// sources are the CIDRs or security groups the rule applies to, no
+	// sources means the rule applies to any source
+	sources []string
+
+	// port is the port as configured, an empty port means all ports
+	port string
+}
+
+// portRulesKnown reports whether all values of the rules configured under key
+// are known. Rules are only checked once they are, as unknown values would be
+// compared as if they were not set.
+func portRulesKnown(d *schema.ResourceDiff, key string) bool {
+	if !d.NewValueKnown(key) {
+		return false
+	}
+
+	raw := d.GetRawConfig()
+	if raw.IsNull() {
+		return true
+	}
+
+	return raw.IsKnown() && raw.GetAttr(key).IsWhollyKnown()
+}
+
+// checkPortRules returns an error for every pair of rules that apply to the
+// same ports and sources. If allowOverlap is set, only exact duplicates are
+// rejected, which is used for ACLs where rules are evaluated in order.
+func checkPortRules(pa portAliases, rules []portRule, allowOverlap bool) error {
+	type portRange struct {
+		start, end int
+	}
+
+	ranges := make([]*portRange, len(rules))
+	for i, r := range rules {
+		if r.port == "" {
+			ranges[i] = &portRange{0, 65535}
+			continue
+		}
+
+		// Invalid ports are reported when the rule is verified
+		start, end, err := pa.parsePortRange(r.port)
+		if err == nil {
+			ranges[i] = &portRange{start, end}
+		}
+	}
+
+	var errs *multierror.Error
+
+	for i := 0; i < len(rules); i++ {
+		for j := i + 1; j < len(rules); j++ {
+			a, b := rules[i], rules[j]
+			if a.scope != b.scope || ranges[i] == nil || ranges[j] == nil {
+				continue
+			}
+
+			source, ok := sharedSource(a.sources, b.sources)
+			if !ok {
+				continue
+			}
+
+			ar, br := ranges[i], ranges[j]
+			if ar.start > br.end || br.start > ar.end {
+				continue
+			}
+
+			duplicate := ar.start == br.start && ar.end == br.end
+			if !duplicate && allowOverlap {
+				continue
+			}
+
+			kind := "overlap"
+			if duplicate {
+				kind = "are duplicates"
+			}
+
+			errs = multierror.Append(errs, fmt.Errorf(
+				"%s %s and %s %s %s for %s",
+				a.scope, describePort(a.port), b.scope, describePort(b.port), kind, source))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// sharedSource returns a source both lists apply to, or "any source" when
+// one of them applies to any source.
+func sharedSource(a, b []string) (string, bool) {
+	if len(a) == 0 || len(b) == 0 {
+		return "any source", true
+	}
+
+	shared := make(map[string]bool, len(a))
+	for _, s := range a {
+		shared[s] = true
+	}
+
+	var sources []string
+	for _, s := range b {
+		if shared[s] {
+			sources = append(sources, s)
+		}
+	}
+
+	if len(sources) == 0 {
+		return "", false
+	}
+
+	sort.Strings(sources)
+
+	return strings.Join(sources, ", "), true
+}
+
+func describePort(port string) string {
+	if port == "" {
+		return "(all ports)"
+	}
+	return fmt.Sprintf("port %q", port)
+}
+
+// configuredACLProtocol returns the configured protocol when it refers to the
+// same protocol as the one returned by the API, so a protocol given by name
+// or number doesn't cause a diff.
+func configuredACLProtocol(configured, actual string) string {
+	if equivalentACLProtocols(configured, actual) {
+		return configured
+	}
+	return actual
+}
+
+// expandPortRules expands a rule with a protocol, ports and ICMP type and code
+// into a port rule per port. The scope of the port rules is the protocol with
+// the given prefix and suffix, e.g. "ingress" and "to 10.0.0.0/8".
+func expandPortRules(rule map[string]interface{}, prefix, suffix string, sources []string) []portRule {
+	protocol := strings.ToLower(rule["protocol"].(string))
+
+	scope := protocol
+	if protocol == "icmp" {
+		icmpType, _ := rule["icmp_type"].(int)
+		icmpCode, _ := rule["icmp_code"].(int)
+		scope = fmt.Sprintf("icmp type %d code %d", icmpType, icmpCode)
+	}
+	scope = strings.TrimSpace(strings.Join([]string{prefix, scope, suffix}, " "))
+
+	var ports []string
+	if protocol == "tcp" || protocol == "udp" {
+		if ps, ok := rule["ports"].(*schema.Set); ok {
+			for _, port := range ps.List() {
+				ports = append(ports, port.(string))
+			}
+		}
+	}
+	if len(ports) == 0 {
+		ports = []string{""}
+	}
+
+	rules := make([]portRule, 0, len(ports))
+	for _, port := range ports {
+		rules = append(rules, portRule{scope: scope, sources: sources, port: port})
+	}
+
+	return rules
+}
+
+// aclPortRules expands ACL rules into port rules. As ACL rules are evaluated
+// in order, the action is part of the scope so an allow and a deny rule for
+// the same ports are not reported.
+func aclPortRules(rules []interface{}) []portRule {
+	var portRules []portRule
+	for _, rule := range rules {
+		rule := rule.(map[string]interface{})
+
+		protocol, err := aclProtocol(rule["protocol"].(string))
+		if err != nil {
+			// Invalid protocols are reported when the rule is verified
+			continue
+		}
+
+		scope := fmt.Sprintf("%s %s %s", rule["traffic_type"], rule["action"], protocol)
+		if protocol == "icmp" {
+			scope = fmt.Sprintf("%s type %d code %d", scope, rule["icmp_type"], rule["icmp_code"])
+		}
+
+		ports := setToStrings(rule["ports"])
+		if port, _ := rule["port"].(string); port != "" {
+			ports = append(ports, port)
+		}
+		if protocol != "tcp" && protocol != "udp" || len(ports) == 0 {
+			ports = []string{""}
+		}
+
+		sources := setToStrings(rule["cidr_list"])
+		for _, port := range ports {
+			portRules = append(portRules, portRule{scope: scope, sources: sources, port: port})
+		}
+	}
+
+	return portRules
+}
+
+// setToStrings returns the values of a set or list of strings sorted.
+func setToStrings(set interface{}) []string {
+	var list []interface{}
+	switch s := set.(type) {
+	case *schema.Set:
+		list = s.List()
+	case []interface{}:
+		list = s
+	default:
+		return nil
+	}
+
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		values = append(values, v.(string))
+	}
+	sort.Strings(values)
+
+	return values
+}
@@ -0,0 +1,216 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePortRange(t *testing.T) {
+	pa, err := newPortAliases(map[string]string{"App": "8000-8010"})
+	if err != nil {
+		t.Fatalf("unexpected error creating port aliases: %s", err)
+	}
+
+	tests := []struct {
+		port      string
+		start     int
+		end       int
+		expectErr bool
+	}{
+		{port: "80", start: 80, end: 80},
+		{port: "80-90", start: 80, end: 90},
+		{port: "https", start: 443, end: 443},
+		{port: "SSH", start: 22, end: 22},
+		{port: "app", start: 8000, end: 8010},
+		{port: "90-80", expectErr: true},
+		{port: "70000", expectErr: true},
+		{port: "unknown", expectErr: true},
+		{port: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		start, end, err := pa.parsePortRange(tt.port)
+		if tt.expectErr {
+			if err == nil {
+				t.Errorf("parsePortRange(%q): expected an error", tt.port)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parsePortRange(%q): unexpected error: %s", tt.port, err)
+			continue
+		}
+		if start != tt.start || end != tt.end {
+			t.Errorf("parsePortRange(%q) = %d-%d, expected %d-%d", tt.port, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestNewPortAliases(t *testing.T) {
+	if _, err := newPortAliases(map[string]string{"80": "8080"}); err == nil {
+		t.Error("expected an error for a numeric alias name")
+	}
+	if _, err := newPortAliases(map[string]string{"app": "https"}); err == nil {
+		t.Error("expected an error for an alias to a service name")
+	}
+}
+
+func TestPortAliasesOf(t *testing.T) {
+	cfg := Config{
+		APIURL:      "http://localhost:8080/client/api",
+		PortAliases: map[string]string{"app": "8000"},
+	}
+
+	cs, err := cfg.NewClient()
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %s", err)
+	}
+
+	if _, _, err := portAliasesOf(cs).parsePortRange("app"); err != nil {
+		t.Errorf("unexpected error parsing a port alias of the client: %s", err)
+	}
+
+	other, err := (&Config{APIURL: cfg.APIURL}).NewClient()
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %s", err)
+	}
+
+	if _, _, err := portAliasesOf(other).parsePortRange("app"); err == nil {
+		t.Error("expected an error parsing a port alias of another client")
+	}
+}
+
+func TestACLProtocol(t *testing.T) {
+	tests := []struct {
+		protocol  string
+		expected  string
+		expectErr bool
+	}{
+		{protocol: "tcp", expected: "tcp"},
+		{protocol: "6", expected: "tcp"},
+		{protocol: "UDP", expected: "udp"},
+		{protocol: "1", expected: "icmp"},
+		{protocol: "all", expected: "all"},
+		{protocol: "gre", expected: "47"},
+		{protocol: "50", expected: "50"},
+		{protocol: "256", expectErr: true},
+		{protocol: "bogus", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		protocol, err := aclProtocol(tt.protocol)
+		if tt.expectErr {
+			if err == nil {
+				t.Errorf("aclProtocol(%q): expected an error", tt.protocol)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("aclProtocol(%q): unexpected error: %s", tt.protocol, err)
+			continue
+		}
+		if protocol != tt.expected {
+			t.Errorf("aclProtocol(%q) = %q, expected %q", tt.protocol, protocol, tt.expected)
+		}
+	}
+}
+
+func TestCheckPortRules(t *testing.T) {
+	tests := []struct {
+		name         string
+		rules        []portRule
+		allowOverlap bool
+		expectErr    string
+	}{
+		{
+			name: "distinct ports",
+			rules: []portRule{
+				{scope: "tcp", sources: []string{"0.0.0.0/0"}, port: "80"},
+				{scope: "tcp", sources: []string{"0.0.0.0/0"}, port: "443"},
+			},
+		},
+		{
+			name: "service name duplicates port number",
+			rules: []portRule{
+				{scope: "tcp", sources: []string{"0.0.0.0/0"}, port: "https"},
+				{scope: "tcp", sources: []string{"0.0.0.0/0"}, port: "443"},
+			},
+			expectErr: `tcp port "https" and tcp port "443" are duplicates for 0.0.0.0/0`,
+		},
+		{
+			name: "overlapping ranges",
+			rules: []portRule{
+				{scope: "tcp", sources: []string{"10.0.0.0/8"}, port: "8000-8100"},
+				{scope: "tcp", sources: []string{"10.0.0.0/8", "192.168.0.0/16"}, port: "8080"},
+			},
+			expectErr: "overlap for 10.0.0.0/8",
+		},
+		{
+			name: "overlapping ranges allowed",
+			rules: []portRule{
+				{scope: "tcp", sources: []string{"10.0.0.0/8"}, port: "8000-8100"},
+				{scope: "tcp", sources: []string{"10.0.0.0/8"}, port: "8080"},
+			},
+			allowOverlap: true,
+		},
+		{
+			name: "different sources",
+			rules: []portRule{
+				{scope: "tcp", sources: []string{"10.0.0.0/8"}, port: "22"},
+				{scope: "tcp", sources: []string{"192.168.0.0/16"}, port: "22"},
+			},
+		},
+		{
+			name: "different scopes",
+			rules: []portRule{
+				{scope: "tcp", port: "53"},
+				{scope: "udp", port: "53"},
+			},
+		},
+		{
+			name: "all ports",
+			rules: []portRule{
+				{scope: "tcp", port: ""},
+				{scope: "tcp", port: "22"},
+			},
+			expectErr: "overlap for any source",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPortRules(nil, tt.rules, tt.allowOverlap)
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error containing %q", tt.expectErr)
+			}
+			if !strings.Contains(err.Error(), tt.expectErr) {
+				t.Fatalf("expected an error containing %q, got: %s", tt.expectErr, err)
+			}
+		})
+	}
+}
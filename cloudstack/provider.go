@@ -75,6 +75,12 @@ func Provider() *schema.Provider {
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_TIMEOUT", 900),
 			},
+
+			"port_aliases": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
@@ -83,9 +89,12 @@ func Provider() *schema.Provider {
 			"cloudstack_autoscale_vm_profile":      dataSourceCloudstackAutoscaleVMProfile(),
 			"cloudstack_condition":                 dataSourceCloudstackCondition(),
 			"cloudstack_counter":                   dataSourceCloudstackCounter(),
+			"cloudstack_disk_offering":             dataSourceCloudstackDiskOffering(),
+			"cloudstack_gslb_rule":                 dataSourceCloudstackGSLBRule(),
 			"cloudstack_template":                  dataSourceCloudstackTemplate(),
 			"cloudstack_ssh_keypair":               dataSourceCloudstackSSHKeyPair(),
 			"cloudstack_instance":                  dataSourceCloudstackInstance(),
+			"cloudstack_network_acl_rules":         dataSourceCloudstackNetworkACLRules(),
 			"cloudstack_network_offering":          dataSourceCloudstackNetworkOffering(),
 			"cloudstack_zone":                      dataSourceCloudStackZone(),
 			"cloudstack_service_offering":          dataSourceCloudstackServiceOffering(),
@@ -111,10 +120,13 @@ func Provider() *schema.Provider {
 
 		ResourcesMap: map[string]*schema.Resource{
 			"cloudstack_affinity_group":                 resourceCloudStackAffinityGroup(),
+			"cloudstack_as_number_range":                resourceCloudStackASNumberRange(),
 			"cloudstack_attach_volume":                  resourceCloudStackAttachVolume(),
 			"cloudstack_autoscale_policy":               resourceCloudStackAutoScalePolicy(),
 			"cloudstack_autoscale_vm_group":             resourceCloudStackAutoScaleVMGroup(),
 			"cloudstack_autoscale_vm_profile":           resourceCloudStackAutoScaleVMProfile(),
+			"cloudstack_bgp_peer":                       resourceCloudStackBGPPeer(),
+			"cloudstack_bgp_peer_association":           resourceCloudStackBGPPeerAssociation(),
 			"cloudstack_cni_configuration":              resourceCloudStackCniConfiguration(),
 			"cloudstack_condition":                      resourceCloudStackCondition(),
 			"cloudstack_configuration":                  resourceCloudStackConfiguration(),
@@ -123,15 +135,23 @@ func Provider() *schema.Provider {
 			"cloudstack_disk":                           resourceCloudStackDisk(),
 			"cloudstack_egress_firewall":                resourceCloudStackEgressFirewall(),
 			"cloudstack_firewall":                       resourceCloudStackFirewall(),
+			"cloudstack_gslb_member":                    resourceCloudStackGSLBMember(),
+			"cloudstack_gslb_rule":                      resourceCloudStackGSLBRule(),
 			"cloudstack_host":                           resourceCloudStackHost(),
 			"cloudstack_instance":                       resourceCloudStackInstance(),
+			"cloudstack_instance_snapshot_policy":       resourceCloudStackInstanceSnapshotPolicy(),
 			"cloudstack_ipaddress":                      resourceCloudStackIPAddress(),
+			"cloudstack_ipv4_subnet":                    resourceCloudStackIPv4Subnet(),
+			"cloudstack_ipv6_firewall":                  resourceCloudStackIPv6Firewall(),
 			"cloudstack_kubernetes_cluster":             resourceCloudStackKubernetesCluster(),
 			"cloudstack_kubernetes_version":             resourceCloudStackKubernetesVersion(),
+			"cloudstack_lb_health_check_policy":         resourceCloudStackLBHealthCheckPolicy(),
+			"cloudstack_lb_stickiness_policy":           resourceCloudStackLBStickinessPolicy(),
 			"cloudstack_loadbalancer":                   resourceCloudStackLoadBalancer(),
 			"cloudstack_loadbalancer_rule":              resourceCloudStackLoadBalancerRule(),
 			"cloudstack_network":                        resourceCloudStackNetwork(),
 			"cloudstack_network_acl":                    resourceCloudStackNetworkACL(),
+			"cloudstack_network_acl_association":        resourceCloudStackNetworkACLAssociation(),
 			"cloudstack_network_acl_rule":               resourceCloudStackNetworkACLRule(),
 			"cloudstack_network_acl_ruleset":            resourceCloudStackNetworkACLRuleset(),
 			"cloudstack_nic":                            resourceCloudStackNIC(),
@@ -140,11 +160,13 @@ func Provider() *schema.Provider {
 			"cloudstack_port_forward":                   resourceCloudStackPortForward(),
 			"cloudstack_network_service_provider_state": resourceCloudStackNetworkServiceProviderState(),
 			"cloudstack_private_gateway":                resourceCloudStackPrivateGateway(),
+			"cloudstack_routing_firewall_rule":          resourceCloudStackRoutingFirewallRule(),
 			"cloudstack_secondary_ipaddress":            resourceCloudStackSecondaryIPAddress(),
 			"cloudstack_secondary_storage":              resourceCloudStackSecondaryStorage(),
 			"cloudstack_security_group":                 resourceCloudStackSecurityGroup(),
 			"cloudstack_security_group_rule":            resourceCloudStackSecurityGroupRule(),
 			"cloudstack_ssh_keypair":                    resourceCloudStackSSHKeyPair(),
+			"cloudstack_ssl_certificate":                resourceCloudStackSSLCertificate(),
 			"cloudstack_static_nat":                     resourceCloudStackStaticNAT(),
 			"cloudstack_static_route":                   resourceCloudStackStaticRoute(),
 			"cloudstack_storage_network_ip_range":       resourceCloudStackStorageNetworkIpRange(),
@@ -156,6 +178,7 @@ func Provider() *schema.Provider {
 			"cloudstack_vpn_connection":                 resourceCloudStackVPNConnection(),
 			"cloudstack_vpn_customer_gateway":           resourceCloudStackVPNCustomerGateway(),
 			"cloudstack_vpn_gateway":                    resourceCloudStackVPNGateway(),
+			"cloudstack_vpn_user":                       resourceCloudStackVPNUser(),
 			"cloudstack_network_offering":               resourceCloudStackNetworkOffering(),
 			"cloudstack_disk_offering":                  resourceCloudStackDiskOffering(),
 			"cloudstack_vlan_ip_range":                  resourceCloudstackVlanIpRange(),
@@ -167,6 +190,7 @@ func Provider() *schema.Provider {
 			"cloudstack_user":                           resourceCloudStackUser(),
 			"cloudstack_domain":                         resourceCloudStackDomain(),
 			"cloudstack_network_service_provider":       resourceCloudStackNetworkServiceProvider(),
+			"cloudstack_remote_access_vpn":              resourceCloudStackRemoteAccessVPN(),
 			"cloudstack_role":                           resourceCloudStackRole(),
 			"cloudstack_role_permission":                resourceCloudStackRolePermission(),
 			"cloudstack_limits":                         resourceCloudStackLimits(),
@@ -216,12 +240,18 @@ func providerConfigure(d *schema.ResourceData) (any, error) {
 		secretKey = section.Key("secretkey").String()
 	}
 
+	aliases := make(map[string]string)
+	for name, port := range d.Get("port_aliases").(map[string]interface{}) {
+		aliases[name] = port.(string)
+	}
+
 	cfg := Config{
 		APIURL:      apiURL.(string),
 		APIKey:      apiKey.(string),
 		SecretKey:   secretKey.(string),
 		HTTPGETOnly: d.Get("http_get_only").(bool),
 		Timeout:     int64(d.Get("timeout").(int)),
+		PortAliases: aliases,
 	}
 
 	return cfg.NewClient()
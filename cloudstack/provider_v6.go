@@ -42,6 +42,7 @@ type CloudstackProviderModel struct {
 	Profile     types.String `tfsdk:"profile"`
 	HttpGetOnly types.Bool   `tfsdk:"http_get_only"`
 	Timeout     types.Int64  `tfsdk:"timeout"`
+	PortAliases types.Map    `tfsdk:"port_aliases"`
 }
 
 var _ provider.Provider = (*CloudstackProvider)(nil)
@@ -80,6 +81,10 @@ func (p *CloudstackProvider) Schema(ctx context.Context, req provider.SchemaRequ
 			"timeout": schema.Int64Attribute{
 				Optional: true,
 			},
+			"port_aliases": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+			},
 		},
 	}
 }
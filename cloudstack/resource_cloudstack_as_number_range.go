@@ -0,0 +1,144 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackASNumberRange() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackASNumberRangeCreate,
+		Read:   resourceCloudStackASNumberRangeRead,
+		Delete: resourceCloudStackASNumberRangeDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackASNumberRangeImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"start_as_number": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"end_as_number": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackASNumberRangeCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	start := d.Get("start_as_number").(int)
+	end := d.Get("end_as_number").(int)
+
+	if start > end {
+		return fmt.Errorf(
+			"The start AS number %d must be lower than or equal to the end AS number %d", start, end)
+	}
+
+	// Create a new parameter struct
+	p := cs.ASNumberRange.NewCreateASNRangeParams(int64(end), int64(start), d.Get("zone_id").(string))
+
+	r, err := cs.ASNumberRange.CreateASNRange(p)
+	if err != nil {
+		return fmt.Errorf("Error creating AS number range %d-%d: %s", start, end, err)
+	}
+
+	d.SetId(r.Id)
+
+	return resourceCloudStackASNumberRangeRead(d, meta)
+}
+
+func resourceCloudStackASNumberRangeRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// AS number ranges can only be listed per zone
+	p := cs.ASNumberRange.NewListASNRangesParams()
+	p.SetZoneid(d.Get("zone_id").(string))
+
+	l, err := cs.ASNumberRange.ListASNRanges(p)
+	if err != nil {
+		return fmt.Errorf("Error retrieving AS number range %s: %s", d.Id(), err)
+	}
+
+	for _, r := range l.ASNRanges {
+		if r.Id == d.Id() {
+			d.Set("zone_id", r.Zoneid)
+			d.Set("start_as_number", r.Startasn)
+			d.Set("end_as_number", r.Endasn)
+			return nil
+		}
+	}
+
+	log.Printf("[DEBUG] AS number range %s does no longer exist", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func resourceCloudStackASNumberRangeDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.ASNumberRange.NewDeleteASNRangeParams(d.Id())
+
+	if _, err := cs.ASNumberRange.DeleteASNRange(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting AS number range %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceCloudStackASNumberRangeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	s := strings.SplitN(d.Id(), "/", 2)
+	if len(s) != 2 {
+		return nil, fmt.Errorf(
+			"Invalid import ID %q, expected <ZONE ID>/<AS NUMBER RANGE ID>", d.Id())
+	}
+
+	d.Set("zone_id", s[0])
+	d.SetId(s[1])
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,163 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackASNumberRange_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackASNumberRangeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackASNumberRange_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackASNumberRangeExists("cloudstack_as_number_range.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_as_number_range.foo", "start_as_number", "64600"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_as_number_range.foo", "end_as_number", "64700"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackASNumberRange_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackASNumberRangeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackASNumberRange_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_as_number_range.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccCloudStackASNumberRangeImportID("cloudstack_as_number_range.foo"),
+			},
+		},
+	})
+}
+
+func testAccCloudStackASNumberRangeImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+
+		return rs.Primary.Attributes["zone_id"] + "/" + rs.Primary.ID, nil
+	}
+}
+
+func testAccCheckCloudStackASNumberRangeExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No AS number range ID is set")
+		}
+
+		found, err := testAccCloudStackASNumberRangeFound(rs)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return fmt.Errorf("AS number range not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackASNumberRangeDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_as_number_range" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No AS number range ID is set")
+		}
+
+		found, err := testAccCloudStackASNumberRangeFound(rs)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			return fmt.Errorf("AS number range %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+// testAccCloudStackASNumberRangeFound reports whether the AS number range
+// exists, as AS number ranges can only be listed per zone.
+func testAccCloudStackASNumberRangeFound(rs *terraform.ResourceState) (bool, error) {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	p := cs.ASNumberRange.NewListASNRangesParams()
+	p.SetZoneid(rs.Primary.Attributes["zone_id"])
+
+	l, err := cs.ASNumberRange.ListASNRanges(p)
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range l.ASNRanges {
+		if r.Id == rs.Primary.ID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+const testAccCloudStackASNumberRange_basic = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_as_number_range" "foo" {
+  zone_id         = data.cloudstack_zone.zone.id
+  start_as_number = 64600
+  end_as_number   = 64700
+}`
@@ -0,0 +1,251 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackBGPPeer() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackBGPPeerCreate,
+		Read:   resourceCloudStackBGPPeerRead,
+		Update: resourceCloudStackBGPPeerUpdate,
+		Delete: resourceCloudStackBGPPeerDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"as_number": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+
+			"ip4_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"ip4_address", "ip6_address"},
+			},
+
+			"ip6_address": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				RequiredWith:  []string{"domain_id"},
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackBGPPeerCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.BGPPeer.NewCreateBgpPeerParams(int64(d.Get("as_number").(int)), d.Get("zone_id").(string))
+
+	if ip4, ok := d.GetOk("ip4_address"); ok {
+		p.SetIp4address(ip4.(string))
+	}
+
+	if ip6, ok := d.GetOk("ip6_address"); ok {
+		p.SetIp6address(ip6.(string))
+	}
+
+	if password, ok := d.GetOk("password"); ok {
+		p.SetPassword(password.(string))
+	}
+
+	// Dedicate the BGP peer right away if requested
+	if account, ok := d.GetOk("account"); ok {
+		p.SetAccount(account.(string))
+	}
+
+	if domainid, ok := d.GetOk("domain_id"); ok {
+		p.SetDomainid(domainid.(string))
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	r, err := cs.BGPPeer.CreateBgpPeer(p)
+	if err != nil {
+		return fmt.Errorf("Error creating BGP peer with AS number %d: %s", d.Get("as_number").(int), err)
+	}
+
+	d.SetId(r.Id)
+
+	return resourceCloudStackBGPPeerRead(d, meta)
+}
+
+func resourceCloudStackBGPPeerRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the BGP peer details
+	b, count, err := cs.BGPPeer.GetBgpPeerByID(d.Id())
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] BGP peer %s does no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("zone_id", b.Zoneid)
+	d.Set("as_number", b.Asnumber)
+	d.Set("ip4_address", b.Ip4address)
+	d.Set("ip6_address", b.Ip6address)
+
+	// A BGP peer is either dedicated to a project or to an account
+	if b.Projectid != "" {
+		setValueOrID(d, "project", b.Project, b.Projectid)
+		d.Set("account", "")
+		d.Set("domain_id", "")
+	} else {
+		d.Set("project", "")
+		d.Set("account", b.Account)
+		d.Set("domain_id", b.Domainid)
+	}
+
+	return nil
+}
+
+func resourceCloudStackBGPPeerUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if d.HasChange("as_number") || d.HasChange("ip4_address") ||
+		d.HasChange("ip6_address") || d.HasChange("password") {
+		// Create a new parameter struct
+		p := cs.BGPPeer.NewUpdateBgpPeerParams(d.Id())
+
+		if d.HasChange("as_number") {
+			p.SetAsnumber(int64(d.Get("as_number").(int)))
+		}
+
+		if d.HasChange("ip4_address") {
+			p.SetIp4address(d.Get("ip4_address").(string))
+		}
+
+		if d.HasChange("ip6_address") {
+			p.SetIp6address(d.Get("ip6_address").(string))
+		}
+
+		if d.HasChange("password") {
+			p.SetPassword(d.Get("password").(string))
+		}
+
+		_, err := cs.BGPPeer.UpdateBgpPeer(p)
+		if err != nil {
+			return fmt.Errorf("Error updating BGP peer %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("account") || d.HasChange("domain_id") || d.HasChange("project") {
+		o, _ := d.GetChange("account")
+		op, _ := d.GetChange("project")
+
+		// Release the BGP peer first if it was dedicated before
+		if o.(string) != "" || op.(string) != "" {
+			p := cs.BGPPeer.NewReleaseBgpPeerParams(d.Id())
+			if _, err := cs.BGPPeer.ReleaseBgpPeer(p); err != nil {
+				return fmt.Errorf("Error releasing BGP peer %s: %s", d.Id(), err)
+			}
+		}
+
+		_, account := d.GetOk("account")
+		_, project := d.GetOk("project")
+
+		if account || project {
+			p := cs.BGPPeer.NewDedicateBgpPeerParams(d.Id())
+
+			if account, ok := d.GetOk("account"); ok {
+				p.SetAccount(account.(string))
+				p.SetDomainid(d.Get("domain_id").(string))
+			}
+
+			// If there is a project supplied, we retrieve and set the project id
+			if err := setProjectid(p, cs, d); err != nil {
+				return err
+			}
+
+			if _, err := cs.BGPPeer.DedicateBgpPeer(p); err != nil {
+				return fmt.Errorf("Error dedicating BGP peer %s: %s", d.Id(), err)
+			}
+		}
+	}
+
+	return resourceCloudStackBGPPeerRead(d, meta)
+}
+
+func resourceCloudStackBGPPeerDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.BGPPeer.NewDeleteBgpPeerParams(d.Id())
+
+	if _, err := cs.BGPPeer.DeleteBgpPeer(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting BGP peer %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
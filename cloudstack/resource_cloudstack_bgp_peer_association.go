@@ -0,0 +1,189 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackBGPPeerAssociation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackBGPPeerAssociationCreate,
+		Read:   resourceCloudStackBGPPeerAssociationRead,
+		Update: resourceCloudStackBGPPeerAssociationUpdate,
+		Delete: resourceCloudStackBGPPeerAssociationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackBGPPeerAssociationImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"network_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"network_id", "vpc_id"},
+			},
+
+			"vpc_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"bgp_peer_ids": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceCloudStackBGPPeerAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := changeBGPPeers(d, meta, setToStringList(d.Get("bgp_peer_ids").(*schema.Set))); err != nil {
+		return err
+	}
+
+	if networkid, ok := d.GetOk("network_id"); ok {
+		d.SetId(networkid.(string))
+	} else {
+		d.SetId(d.Get("vpc_id").(string))
+	}
+
+	return resourceCloudStackBGPPeerAssociationRead(d, meta)
+}
+
+func resourceCloudStackBGPPeerAssociationRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	var peers []string
+
+	if networkid, ok := d.GetOk("network_id"); ok {
+		n, count, err := cs.Network.GetNetworkByID(networkid.(string), cloudstack.WithProject("-1"))
+		if err != nil {
+			if count == 0 {
+				log.Printf("[DEBUG] Network %s does no longer exist", networkid.(string))
+				d.SetId("")
+				return nil
+			}
+
+			return err
+		}
+
+		for _, peer := range n.Bgppeers {
+			peers = append(peers, peer.Id)
+		}
+	} else {
+		v, count, err := cs.VPC.GetVPCByID(d.Get("vpc_id").(string), cloudstack.WithProject("-1"))
+		if err != nil {
+			if count == 0 {
+				log.Printf("[DEBUG] VPC %s does no longer exist", d.Get("vpc_id").(string))
+				d.SetId("")
+				return nil
+			}
+
+			return err
+		}
+
+		for _, peer := range v.Bgppeers {
+			peers = append(peers, peer.Id)
+		}
+	}
+
+	d.Set("bgp_peer_ids", peers)
+
+	return nil
+}
+
+func resourceCloudStackBGPPeerAssociationUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("bgp_peer_ids") {
+		err := changeBGPPeers(d, meta, setToStringList(d.Get("bgp_peer_ids").(*schema.Set)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackBGPPeerAssociationRead(d, meta)
+}
+
+func resourceCloudStackBGPPeerAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+	// Changing to an empty list removes all BGP peers
+	return changeBGPPeers(d, meta, []string{})
+}
+
+func resourceCloudStackBGPPeerAssociationImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	id := d.Id()
+
+	// The ID is either the ID of a network or of a VPC
+	_, count, err := cs.Network.GetNetworkByID(id, cloudstack.WithProject("-1"))
+	if err == nil {
+		d.Set("network_id", id)
+		return []*schema.ResourceData{d}, nil
+	}
+	if count != 0 {
+		return nil, err
+	}
+
+	_, count, err = cs.VPC.GetVPCByID(id, cloudstack.WithProject("-1"))
+	if err != nil {
+		if count == 0 {
+			return nil, fmt.Errorf("No network or VPC with ID %s exists", id)
+		}
+		return nil, err
+	}
+	d.Set("vpc_id", id)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// changeBGPPeers replaces the BGP peers of either a network or a VPC.
+func changeBGPPeers(d *schema.ResourceData, meta interface{}, peers []string) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if networkid, ok := d.GetOk("network_id"); ok {
+		p := cs.BGPPeer.NewChangeBgpPeersForNetworkParams(networkid.(string))
+		p.SetBgppeerids(peers)
+
+		if _, err := cs.BGPPeer.ChangeBgpPeersForNetwork(p); err != nil {
+			return fmt.Errorf(
+				"Error changing BGP peers of network %s: %s", networkid.(string), err)
+		}
+
+		return nil
+	}
+
+	vpcid := d.Get("vpc_id").(string)
+
+	p := cs.BGPPeer.NewChangeBgpPeersForVpcParams(vpcid)
+	p.SetBgppeerids(peers)
+
+	if _, err := cs.BGPPeer.ChangeBgpPeersForVpc(p); err != nil {
+		return fmt.Errorf("Error changing BGP peers of VPC %s: %s", vpcid, err)
+	}
+
+	return nil
+}
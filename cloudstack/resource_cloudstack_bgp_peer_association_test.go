@@ -0,0 +1,252 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackBGPPeerAssociation_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackBGPPeerAssociationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackBGPPeerAssociation_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackBGPPeerAssociationPeers(
+						"cloudstack_bgp_peer_association.foo", 1),
+					resource.TestCheckResourceAttr(
+						"cloudstack_bgp_peer_association.foo", "bgp_peer_ids.#", "1"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackBGPPeerAssociation_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackBGPPeerAssociationPeers(
+						"cloudstack_bgp_peer_association.foo", 2),
+					resource.TestCheckResourceAttr(
+						"cloudstack_bgp_peer_association.foo", "bgp_peer_ids.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackBGPPeerAssociation_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackBGPPeerAssociationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackBGPPeerAssociation_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_bgp_peer_association.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackBGPPeerAssociationPeers(n string, peers int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No network ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		network, _, err := cs.Network.GetNetworkByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(network.Bgppeers) != peers {
+			return fmt.Errorf(
+				"Expected %d BGP peers for network %s, got %d", peers, rs.Primary.ID, len(network.Bgppeers))
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackBGPPeerAssociationDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_bgp_peer_association" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No network ID is set")
+		}
+
+		// The network itself is destroyed as well, if it still exists it
+		// should not have any BGP peers left
+		network, _, err := cs.Network.GetNetworkByID(rs.Primary.ID)
+		if err == nil && len(network.Bgppeers) > 0 {
+			return fmt.Errorf("Network %s still has BGP peers", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackBGPPeerAssociation_basic = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_ipv4_subnet" "foo" {
+  subnet  = "172.30.0.0/20"
+  zone_id = data.cloudstack_zone.zone.id
+}
+
+resource "cloudstack_as_number_range" "foo" {
+  zone_id         = data.cloudstack_zone.zone.id
+  start_as_number = 64600
+  end_as_number   = 64700
+}
+
+resource "cloudstack_network_offering" "routed" {
+  name               = "terraform-routed-offering"
+  display_text       = "terraform-routed-offering"
+  guest_ip_type      = "Isolated"
+  traffic_type       = "Guest"
+  network_mode       = "ROUTED"
+  routing_mode       = "Dynamic"
+  specify_as_number  = true
+  enable             = true
+  supported_services = ["Dhcp", "Dns", "Firewall", "UserData"]
+  service_provider_list = {
+    Dhcp     = "VirtualRouter"
+    Dns      = "VirtualRouter"
+    Firewall = "VirtualRouter"
+    UserData = "VirtualRouter"
+  }
+}
+
+resource "cloudstack_network" "foo" {
+  name             = "terraform-routed-network"
+  cidr             = "172.30.1.0/24"
+  network_offering = cloudstack_network_offering.routed.id
+  zone             = data.cloudstack_zone.zone.name
+
+  depends_on = [
+    cloudstack_ipv4_subnet.foo,
+    cloudstack_as_number_range.foo,
+  ]
+}
+
+resource "cloudstack_bgp_peer" "foo" {
+  zone_id     = data.cloudstack_zone.zone.id
+  as_number   = 65001
+  ip4_address = "10.0.0.1"
+}
+
+resource "cloudstack_bgp_peer_association" "foo" {
+  network_id   = cloudstack_network.foo.id
+  bgp_peer_ids = [cloudstack_bgp_peer.foo.id]
+}`
+
+const testAccCloudStackBGPPeerAssociation_update = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_ipv4_subnet" "foo" {
+  subnet  = "172.30.0.0/20"
+  zone_id = data.cloudstack_zone.zone.id
+}
+
+resource "cloudstack_as_number_range" "foo" {
+  zone_id         = data.cloudstack_zone.zone.id
+  start_as_number = 64600
+  end_as_number   = 64700
+}
+
+resource "cloudstack_network_offering" "routed" {
+  name               = "terraform-routed-offering"
+  display_text       = "terraform-routed-offering"
+  guest_ip_type      = "Isolated"
+  traffic_type       = "Guest"
+  network_mode       = "ROUTED"
+  routing_mode       = "Dynamic"
+  specify_as_number  = true
+  enable             = true
+  supported_services = ["Dhcp", "Dns", "Firewall", "UserData"]
+  service_provider_list = {
+    Dhcp     = "VirtualRouter"
+    Dns      = "VirtualRouter"
+    Firewall = "VirtualRouter"
+    UserData = "VirtualRouter"
+  }
+}
+
+resource "cloudstack_network" "foo" {
+  name             = "terraform-routed-network"
+  cidr             = "172.30.1.0/24"
+  network_offering = cloudstack_network_offering.routed.id
+  zone             = data.cloudstack_zone.zone.name
+
+  depends_on = [
+    cloudstack_ipv4_subnet.foo,
+    cloudstack_as_number_range.foo,
+  ]
+}
+
+resource "cloudstack_bgp_peer" "foo" {
+  zone_id     = data.cloudstack_zone.zone.id
+  as_number   = 65001
+  ip4_address = "10.0.0.1"
+}
+
+resource "cloudstack_bgp_peer" "bar" {
+  zone_id     = data.cloudstack_zone.zone.id
+  as_number   = 65002
+  ip4_address = "10.0.0.2"
+}
+
+resource "cloudstack_bgp_peer_association" "foo" {
+  network_id   = cloudstack_network.foo.id
+  bgp_peer_ids = [cloudstack_bgp_peer.foo.id, cloudstack_bgp_peer.bar.id]
+}`
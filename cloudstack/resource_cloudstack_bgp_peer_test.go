@@ -0,0 +1,149 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackBGPPeer_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackBGPPeerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackBGPPeer_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackBGPPeerExists("cloudstack_bgp_peer.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_bgp_peer.foo", "as_number", "65001"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackBGPPeer_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackBGPPeerExists("cloudstack_bgp_peer.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_bgp_peer.foo", "ip4_address", "10.0.0.2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackBGPPeer_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackBGPPeerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackBGPPeer_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_bgp_peer.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackBGPPeerExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No BGP peer ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		peer, _, err := cs.BGPPeer.GetBgpPeerByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if peer.Id != rs.Primary.ID {
+			return fmt.Errorf("BGP peer not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackBGPPeerDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_bgp_peer" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No BGP peer ID is set")
+		}
+
+		_, _, err := cs.BGPPeer.GetBgpPeerByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("BGP peer %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackBGPPeer_basic = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_bgp_peer" "foo" {
+  zone_id     = data.cloudstack_zone.zone.id
+  as_number   = 65001
+  ip4_address = "10.0.0.1"
+}`
+
+const testAccCloudStackBGPPeer_update = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_bgp_peer" "foo" {
+  zone_id     = data.cloudstack_zone.zone.id
+  as_number   = 65001
+  ip4_address = "10.0.0.2"
+}`
@@ -21,6 +21,7 @@ package cloudstack
 
 import (
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -92,6 +93,12 @@ func resourceCloudStackDisk() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"storage_pool": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
 			"reattach_on_change": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -144,6 +151,15 @@ func resourceCloudStackDiskCreate(d *schema.ResourceData, meta interface{}) erro
 	// Set the zone ID
 	p.SetZoneid(zoneid)
 
+	// If a storage pool is supplied, create the volume on that pool
+	if v, ok := d.GetOk("storage_pool"); ok {
+		storageid, e := retrieveID(cs, "storage_pool", v.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetStorageid(storageid)
+	}
+
 	// Create the new volume
 	r, err := cs.Volume.CreateVolume(p)
 	if err != nil {
@@ -213,6 +229,7 @@ func resourceCloudStackDiskRead(d *schema.ResourceData, meta interface{}) error
 
 	setValueOrID(d, "disk_offering", v.Diskofferingname, v.Diskofferingid)
 	setValueOrID(d, "project", v.Project, v.Projectid)
+	setValueOrID(d, "storage_pool", v.Storage, v.Storageid)
 	setValueOrID(d, "zone", v.Zonename, v.Zoneid)
 
 	if v.Virtualmachineid != "" {
@@ -266,6 +283,13 @@ func resourceCloudStackDiskUpdate(d *schema.ResourceData, meta interface{}) erro
 		d.SetId(r.Id)
 	}
 
+	if d.HasChange("storage_pool") {
+		if err := resourceCloudStackDiskMigrate(d, meta); err != nil {
+			return fmt.Errorf("Error migrating disk %s to storage pool %s: %s",
+				name, d.Get("storage_pool").(string), err)
+		}
+	}
+
 	// If the device ID changed, just detach here so we can re-attach the
 	// volume at the end of this function
 	if d.HasChange("device_id") || d.HasChange("virtual_machine") {
@@ -412,6 +436,44 @@ func resourceCloudStackDiskDetach(d *schema.ResourceData, meta interface{}) erro
 	return err
 }
 
+func resourceCloudStackDiskMigrate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Retrieve the storage pool ID
+	storageid, e := retrieveID(cs, "storage_pool", d.Get("storage_pool").(string))
+	if e != nil {
+		return e.Error()
+	}
+
+	attached, err := isAttached(d, meta)
+	if err != nil {
+		return err
+	}
+
+	if !attached {
+		return migrateVolume(cs, d, storageid, false)
+	}
+
+	// Try to live migrate the attached volume first
+	err = migrateVolume(cs, d, storageid, true)
+	if err == nil || !d.Get("reattach_on_change").(bool) {
+		return err
+	}
+
+	log.Printf("[DEBUG] Live migration of disk %s failed, retrying while detached: %s", d.Id(), err)
+
+	// Detach the volume, migrate it and re-attach it again
+	if err := resourceCloudStackDiskDetach(d, meta); err != nil {
+		return err
+	}
+
+	if err := migrateVolume(cs, d, storageid, false); err != nil {
+		return err
+	}
+
+	return resourceCloudStackDiskAttach(d, meta)
+}
+
 func isAttached(d *schema.ResourceData, meta interface{}) (bool, error) {
 	cs := meta.(*cloudstack.CloudStackClient)
 
@@ -20,32 +20,155 @@
 package cloudstack
 
 import (
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// diskOfferingRateLimits lists the I/O rate limit attributes of a disk
+// offering, which can all be set on create and changed on update
+var diskOfferingRateLimits = []string{
+	"bytes_read_rate",
+	"bytes_read_rate_max",
+	"bytes_read_rate_max_length",
+	"bytes_write_rate",
+	"bytes_write_rate_max",
+	"bytes_write_rate_max_length",
+	"iops_read_rate",
+	"iops_read_rate_max",
+	"iops_read_rate_max_length",
+	"iops_write_rate",
+	"iops_write_rate_max",
+	"iops_write_rate_max_length",
+}
+
 func resourceCloudStackDiskOffering() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"display_text": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"disk_size": {
+			Description:   "The size of the disk offering in GB",
+			Type:          schema.TypeInt,
+			Optional:      true,
+			Computed:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"customized"},
+		},
+		"customized": {
+			Description: "Whether the disk offering allows a custom disk size",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+		},
+		"customized_iops": {
+			Description: "Whether the disk offering allows custom IOPS",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+		},
+		"min_iops": {
+			Description: "The minimum IOPS of the disk offering",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+		},
+		"max_iops": {
+			Description: "The maximum IOPS of the disk offering",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+		},
+		"hypervisor_snapshot_reserve": {
+			Description: "Hypervisor snapshot reserve space as a percent of a volume (for managed storage)",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+		},
+		"storage_tags": {
+			Description: "Storage tags to associate with the disk offering",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+		},
+		"provisioning_type": {
+			Description:  "The provisioning type of the disk offering. Values are thin, sparse and fat",
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{"thin", "sparse", "fat"}, false),
+		},
+		"cache_mode": {
+			Description:  "The cache mode of the disk offering. Values are none, writeback and writethrough",
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.StringInSlice([]string{"none", "writeback", "writethrough"}, false),
+		},
+		"encrypt": {
+			Description: "Encrypt volumes created from this disk offering",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+		},
+		"disk_size_strictness": {
+			Description: "Whether the disk size of volumes created from this offering can not be changed",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+		},
+		"domain_ids": {
+			Description: "The IDs of the domains the disk offering is available in",
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+		},
+		"zone_ids": {
+			Description: "The IDs of the zones the disk offering is available in",
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+		},
+	}
+
+	for _, k := range diskOfferingRateLimits {
+		s[k] = &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		}
+	}
+
 	return &schema.Resource{
 		Create: resourceCloudStackDiskOfferingCreate,
 		Read:   resourceCloudStackDiskOfferingRead,
 		Update: resourceCloudStackDiskOfferingUpdate,
 		Delete: resourceCloudStackDiskOfferingDelete,
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"display_text": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"disk_size": {
-				Type:     schema.TypeInt,
-				Required: true,
-			},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
 		},
+		Schema: s,
 	}
 }
 
@@ -53,17 +176,103 @@ func resourceCloudStackDiskOfferingCreate(d *schema.ResourceData, meta interface
 	cs := meta.(*cloudstack.CloudStackClient)
 	name := d.Get("name").(string)
 	display_text := d.Get("display_text").(string)
-	disk_size := d.Get("disk_size").(int)
 
 	// Create a new parameter struct
-	p := cs.DiskOffering.NewCreateDiskOfferingParams(name, display_text)
-	p.SetDisksize(int64(disk_size))
+	p := cs.DiskOffering.NewCreateDiskOfferingParams(display_text, name)
+
+	if v, ok := d.GetOk("disk_size"); ok {
+		p.SetDisksize(int64(v.(int)))
+	}
+
+	if v, ok := d.GetOk("customized"); ok {
+		p.SetCustomized(v.(bool))
+	}
+
+	if v, ok := d.GetOk("customized_iops"); ok {
+		p.SetCustomizediops(v.(bool))
+	}
+
+	if v, ok := d.GetOk("min_iops"); ok {
+		p.SetMiniops(int64(v.(int)))
+	}
+
+	if v, ok := d.GetOk("max_iops"); ok {
+		p.SetMaxiops(int64(v.(int)))
+	}
+
+	if v, ok := d.GetOk("hypervisor_snapshot_reserve"); ok {
+		p.SetHypervisorsnapshotreserve(v.(int))
+	}
+
+	if v, ok := d.GetOk("storage_tags"); ok {
+		p.SetTags(v.(string))
+	}
+
+	if v, ok := d.GetOk("provisioning_type"); ok {
+		p.SetProvisioningtype(v.(string))
+	}
+
+	if v, ok := d.GetOk("cache_mode"); ok {
+		p.SetCachemode(v.(string))
+	}
+
+	if v, ok := d.GetOk("encrypt"); ok {
+		p.SetEncrypt(v.(bool))
+	}
+
+	if v, ok := d.GetOk("disk_size_strictness"); ok {
+		p.SetDisksizestrictness(v.(bool))
+	}
+
+	if v, ok := d.GetOk("domain_ids"); ok {
+		p.SetDomainid(setToStringList(v.(*schema.Set)))
+	}
+
+	if v, ok := d.GetOk("zone_ids"); ok {
+		p.SetZoneid(setToStringList(v.(*schema.Set)))
+	}
+
+	if v, ok := d.GetOk("bytes_read_rate"); ok {
+		p.SetBytesreadrate(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("bytes_read_rate_max"); ok {
+		p.SetBytesreadratemax(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("bytes_read_rate_max_length"); ok {
+		p.SetBytesreadratemaxlength(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("bytes_write_rate"); ok {
+		p.SetByteswriterate(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("bytes_write_rate_max"); ok {
+		p.SetByteswriteratemax(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("bytes_write_rate_max_length"); ok {
+		p.SetByteswriteratemaxlength(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("iops_read_rate"); ok {
+		p.SetIopsreadrate(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("iops_read_rate_max"); ok {
+		p.SetIopsreadratemax(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("iops_read_rate_max_length"); ok {
+		p.SetIopsreadratemaxlength(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("iops_write_rate"); ok {
+		p.SetIopswriterate(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("iops_write_rate_max"); ok {
+		p.SetIopswriteratemax(int64(v.(int)))
+	}
+	if v, ok := d.GetOk("iops_write_rate_max_length"); ok {
+		p.SetIopswriteratemaxlength(int64(v.(int)))
+	}
 
 	log.Printf("[DEBUG] Creating Disk Offering %s", name)
 	diskOff, err := cs.DiskOffering.CreateDiskOffering(p)
-
 	if err != nil {
-		return err
+		return fmt.Errorf("Error creating disk offering %s: %s", name, err)
 	}
 
 	log.Printf("[DEBUG] Disk Offering %s successfully created", name)
@@ -72,8 +281,189 @@ func resourceCloudStackDiskOfferingCreate(d *schema.ResourceData, meta interface
 	return resourceCloudStackDiskOfferingRead(d, meta)
 }
 
-func resourceCloudStackDiskOfferingRead(d *schema.ResourceData, meta interface{}) error { return nil }
+func resourceCloudStackDiskOfferingRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+	log.Printf("[DEBUG] Retrieving Disk Offering %s", d.Get("name").(string))
+
+	// Get the Disk Offering details
+	o, count, err := cs.DiskOffering.GetDiskOfferingByID(d.Id())
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Disk Offering %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"name":                        o.Name,
+		"display_text":                o.Displaytext,
+		"disk_size":                   int(o.Disksize),
+		"customized":                  o.Iscustomized,
+		"customized_iops":             o.Iscustomizediops,
+		"min_iops":                    int(o.Miniops),
+		"max_iops":                    int(o.Maxiops),
+		"hypervisor_snapshot_reserve": o.Hypervisorsnapshotreserve,
+		"storage_tags":                o.Tags,
+		"provisioning_type":           o.Provisioningtype,
+		"cache_mode":                  o.Cachemode,
+		"encrypt":                     o.Encrypt,
+		"disk_size_strictness":        o.Disksizestrictness,
+		"domain_ids":                  splitIDList(o.Domainid),
+		"zone_ids":                    splitIDList(o.Zoneid),
+	}
+
+	for k, v := range fields {
+		d.Set(k, v)
+	}
+
+	for k, v := range diskOfferingRateLimitValues(o) {
+		d.Set(k, v)
+	}
+
+	return nil
+}
+
+// diskOfferingRateLimitValues returns the value of each of the
+// diskOfferingRateLimits of the given disk offering
+func diskOfferingRateLimitValues(o *cloudstack.DiskOffering) map[string]int {
+	return map[string]int{
+		"bytes_read_rate":             int(o.Diskbytesreadrate),
+		"bytes_read_rate_max":         int(o.Diskbytesreadratemax),
+		"bytes_read_rate_max_length":  int(o.Diskbytesreadratemaxlength),
+		"bytes_write_rate":            int(o.Diskbyteswriterate),
+		"bytes_write_rate_max":        int(o.Diskbyteswriteratemax),
+		"bytes_write_rate_max_length": int(o.Diskbyteswriteratemaxlength),
+		"iops_read_rate":              int(o.Diskiopsreadrate),
+		"iops_read_rate_max":          int(o.Diskiopsreadratemax),
+		"iops_read_rate_max_length":   int(o.Diskiopsreadratemaxlength),
+		"iops_write_rate":             int(o.Diskiopswriterate),
+		"iops_write_rate_max":         int(o.Diskiopswriteratemax),
+		"iops_write_rate_max_length":  int(o.Diskiopswriteratemaxlength),
+	}
+}
+
+func resourceCloudStackDiskOfferingUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+	name := d.Get("name").(string)
+
+	// Create a new parameter struct
+	p := cs.DiskOffering.NewUpdateDiskOfferingParams(d.Id())
+
+	if d.HasChange("name") {
+		p.SetName(name)
+	}
+
+	if d.HasChange("display_text") {
+		p.SetDisplaytext(d.Get("display_text").(string))
+	}
+
+	if d.HasChange("storage_tags") {
+		p.SetTags(d.Get("storage_tags").(string))
+	}
+
+	if d.HasChange("cache_mode") {
+		p.SetCachemode(d.Get("cache_mode").(string))
+	}
+
+	// An empty list makes the offering public again
+	if d.HasChange("domain_ids") {
+		domainids := "public"
+		if l := setToStringList(d.Get("domain_ids").(*schema.Set)); len(l) > 0 {
+			domainids = strings.Join(l, ",")
+		}
+		p.SetDomainid(domainids)
+	}
+
+	// An empty list makes the offering available in all zones again
+	if d.HasChange("zone_ids") {
+		zoneids := "all"
+		if l := setToStringList(d.Get("zone_ids").(*schema.Set)); len(l) > 0 {
+			zoneids = strings.Join(l, ",")
+		}
+		p.SetZoneid(zoneids)
+	}
+
+	if d.HasChange("bytes_read_rate") {
+		p.SetBytesreadrate(int64(d.Get("bytes_read_rate").(int)))
+	}
+	if d.HasChange("bytes_read_rate_max") {
+		p.SetBytesreadratemax(int64(d.Get("bytes_read_rate_max").(int)))
+	}
+	if d.HasChange("bytes_read_rate_max_length") {
+		p.SetBytesreadratemaxlength(int64(d.Get("bytes_read_rate_max_length").(int)))
+	}
+	if d.HasChange("bytes_write_rate") {
+		p.SetByteswriterate(int64(d.Get("bytes_write_rate").(int)))
+	}
+	if d.HasChange("bytes_write_rate_max") {
+		p.SetByteswriteratemax(int64(d.Get("bytes_write_rate_max").(int)))
+	}
+	if d.HasChange("bytes_write_rate_max_length") {
+		p.SetByteswriteratemaxlength(int64(d.Get("bytes_write_rate_max_length").(int)))
+	}
+	if d.HasChange("iops_read_rate") {
+		p.SetIopsreadrate(int64(d.Get("iops_read_rate").(int)))
+	}
+	if d.HasChange("iops_read_rate_max") {
+		p.SetIopsreadratemax(int64(d.Get("iops_read_rate_max").(int)))
+	}
+	if d.HasChange("iops_read_rate_max_length") {
+		p.SetIopsreadratemaxlength(int64(d.Get("iops_read_rate_max_length").(int)))
+	}
+	if d.HasChange("iops_write_rate") {
+		p.SetIopswriterate(int64(d.Get("iops_write_rate").(int)))
+	}
+	if d.HasChange("iops_write_rate_max") {
+		p.SetIopswriteratemax(int64(d.Get("iops_write_rate_max").(int)))
+	}
+	if d.HasChange("iops_write_rate_max_length") {
+		p.SetIopswriteratemaxlength(int64(d.Get("iops_write_rate_max_length").(int)))
+	}
+
+	log.Printf("[DEBUG] Updating Disk Offering %s", name)
+	if _, err := cs.DiskOffering.UpdateDiskOffering(p); err != nil {
+		return fmt.Errorf("Error updating disk offering %s: %s", name, err)
+	}
+
+	return resourceCloudStackDiskOfferingRead(d, meta)
+}
+
+func resourceCloudStackDiskOfferingDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.DiskOffering.NewDeleteDiskOfferingParams(d.Id())
+
+	log.Printf("[INFO] Deleting Disk Offering: %s", d.Get("name").(string))
+	if _, err := cs.DiskOffering.DeleteDiskOffering(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
 
-func resourceCloudStackDiskOfferingUpdate(d *schema.ResourceData, meta interface{}) error { return nil }
+		return fmt.Errorf("Error deleting disk offering %s: %s", d.Get("name").(string), err)
+	}
 
-func resourceCloudStackDiskOfferingDelete(d *schema.ResourceData, meta interface{}) error { return nil }
+	return nil
+}
+
+// setToStringList returns the elements of a set of strings as a string slice
+func setToStringList(s *schema.Set) []string {
+	l := make([]string, 0, s.Len())
+	for _, v := range s.List() {
+		l = append(l, v.(string))
+	}
+	return l
+}
+
+// splitIDList splits a comma separated list of IDs as returned by the API
+func splitIDList(ids string) []string {
+	if ids == "" {
+		return []string{}
+	}
+	return strings.Split(ids, ",")
+}
@@ -0,0 +1,194 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackDiskOffering_basic(t *testing.T) {
+	var diskOffering cloudstack.DiskOffering
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackDiskOfferingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackDiskOffering_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackDiskOfferingExists("cloudstack_disk_offering.foo", &diskOffering),
+					resource.TestCheckResourceAttr("cloudstack_disk_offering.foo", "disk_size", "10"),
+					resource.TestCheckResourceAttr("cloudstack_disk_offering.foo", "provisioning_type", "thin"),
+					resource.TestCheckResourceAttr("cloudstack_disk_offering.foo", "storage_tags", "ssd"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackDiskOffering_customized(t *testing.T) {
+	var diskOffering cloudstack.DiskOffering
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackDiskOfferingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackDiskOffering_customized,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackDiskOfferingExists("cloudstack_disk_offering.foo", &diskOffering),
+					resource.TestCheckResourceAttr("cloudstack_disk_offering.foo", "customized", "true"),
+					resource.TestCheckResourceAttr("cloudstack_disk_offering.foo", "customized_iops", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackDiskOffering_update(t *testing.T) {
+	var diskOffering cloudstack.DiskOffering
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackDiskOfferingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackDiskOffering_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackDiskOfferingExists("cloudstack_disk_offering.foo", &diskOffering),
+				),
+			},
+			{
+				Config: testAccCloudStackDiskOffering_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackDiskOfferingExists("cloudstack_disk_offering.foo", &diskOffering),
+					resource.TestCheckResourceAttr("cloudstack_disk_offering.foo", "display_text", "terraform-disk-offering-updated"),
+					resource.TestCheckResourceAttr("cloudstack_disk_offering.foo", "storage_tags", "ssd,fast"),
+					resource.TestCheckResourceAttr("cloudstack_disk_offering.foo", "bytes_read_rate", "104857600"),
+					resource.TestCheckResourceAttr("cloudstack_disk_offering.foo", "iops_write_rate", "500"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackDiskOffering_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackDiskOfferingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackDiskOffering_basic,
+			},
+			{
+				ResourceName:      "cloudstack_disk_offering.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackDiskOfferingExists(n string, diskOffering *cloudstack.DiskOffering) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No disk offering ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		o, _, err := cs.DiskOffering.GetDiskOfferingByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if o.Id != rs.Primary.ID {
+			return fmt.Errorf("Disk offering not found")
+		}
+
+		*diskOffering = *o
+		return nil
+	}
+}
+
+func testAccCheckCloudStackDiskOfferingDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_disk_offering" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No disk offering ID is set")
+		}
+
+		_, _, err := cs.DiskOffering.GetDiskOfferingByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Disk offering %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackDiskOffering_basic = `
+resource "cloudstack_disk_offering" "foo" {
+  name              = "terraform-disk-offering"
+  display_text      = "terraform-disk-offering"
+  disk_size         = 10
+  provisioning_type = "thin"
+  storage_tags      = "ssd"
+}
+`
+
+const testAccCloudStackDiskOffering_customized = `
+resource "cloudstack_disk_offering" "foo" {
+  name            = "terraform-disk-offering-custom"
+  display_text    = "terraform-disk-offering-custom"
+  customized      = true
+  customized_iops = true
+}
+`
+
+const testAccCloudStackDiskOffering_update = `
+resource "cloudstack_disk_offering" "foo" {
+  name              = "terraform-disk-offering"
+  display_text      = "terraform-disk-offering-updated"
+  disk_size         = 10
+  provisioning_type = "thin"
+  storage_tags      = "ssd,fast"
+  bytes_read_rate   = 104857600
+  iops_write_rate   = 500
+}
+`
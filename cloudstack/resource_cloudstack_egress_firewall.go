@@ -20,23 +20,29 @@
 package cloudstack
 
 import (
+	"context"
 	"fmt"
-	"strconv"
+	"sort"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceCloudStackEgressFirewall() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCloudStackEgressFirewallCreate,
-		Read:   resourceCloudStackEgressFirewallRead,
-		Update: resourceCloudStackEgressFirewallUpdate,
-		Delete: resourceCloudStackEgressFirewallDelete,
+		CreateContext: warnEgressPolicyConflicts(resourceCloudStackEgressFirewallCreate),
+		ReadContext:   warnEgressPolicyConflicts(resourceCloudStackEgressFirewallRead),
+		UpdateContext: warnEgressPolicyConflicts(resourceCloudStackEgressFirewallUpdate),
+		Delete:        resourceCloudStackEgressFirewallDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackEgressFirewallImport,
+		},
+		CustomizeDiff: resourceCloudStackEgressFirewallCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"network_id": {
@@ -58,6 +64,19 @@ func resourceCloudStackEgressFirewall() *schema.Resource {
 				Default:  false,
 			},
 
+			"default_policy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+			},
+
+			"policy_conflicts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
 			"rule": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -101,6 +120,17 @@ func resourceCloudStackEgressFirewall() *schema.Resource {
 							Set:      schema.HashString,
 						},
 
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"tags": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+
 						"uuids": {
 							Type:     schema.TypeMap,
 							Computed: true,
@@ -147,32 +177,31 @@ func resourceCloudStackEgressFirewallCreate(d *schema.ResourceData, meta interfa
 
 func createEgressFirewallRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, nrs *schema.Set) error {
 	var errs *multierror.Error
+	var mu sync.Mutex
 
 	var wg sync.WaitGroup
 	wg.Add(nrs.Len())
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
+	sem := make(jobSlots, d.Get("parallelism").(int))
 	for _, rule := range nrs.List() {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Create a single rule
-			err := createEgressFirewallRule(d, meta, rule)
+			err := createEgressFirewallRule(d, meta, sem, rule)
 
 			// If we have at least one UUID, we need to save the rule
 			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				mu.Lock()
 				rules.Add(rule)
+				mu.Unlock()
 			}
 
 			if err != nil {
-				errs = multierror.Append(errs, err)
+				mu.Lock()
+				errs = multierror.Append(errs, ruleError("creating", rule, err))
+				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -180,12 +209,12 @@ func createEgressFirewallRules(d *schema.ResourceData, meta interface{}, rules *
 
 	return errs.ErrorOrNil()
 }
-func createEgressFirewallRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
+func createEgressFirewallRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 	uuids := rule["uuids"].(map[string]interface{})
 
 	// Make sure all required rule parameters are there
-	if err := verifyEgressFirewallRuleParams(d, rule); err != nil {
+	if err := verifyEgressFirewallRuleParams(d, portAliasesOf(cs), rule); err != nil {
 		return err
 	}
 
@@ -215,7 +244,12 @@ func createEgressFirewallRule(d *schema.ResourceData, meta interface{}, rule map
 		p.SetIcmptype(rule["icmp_type"].(int))
 		p.SetIcmpcode(rule["icmp_code"].(int))
 
-		r, err := cs.Firewall.CreateEgressFirewallRule(p)
+		sem.take()
+		r, err := jobs(cs).client().Firewall.CreateEgressFirewallRule(p)
+		sem.release()
+		if err == nil {
+			err = jobs(cs).wait(r.JobID)
+		}
 		if err != nil {
 			return err
 		}
@@ -237,25 +271,20 @@ func createEgressFirewallRule(d *schema.ResourceData, meta interface{}, rule map
 					continue
 				}
 
-				m := splitPorts.FindStringSubmatch(port.(string))
-
-				startPort, err := strconv.Atoi(m[1])
+				startPort, endPort, err := portAliasesOf(cs).parsePortRange(port.(string))
 				if err != nil {
 					return err
 				}
 
-				endPort := startPort
-				if m[2] != "" {
-					endPort, err = strconv.Atoi(m[2])
-					if err != nil {
-						return err
-					}
-				}
-
 				p.SetStartport(startPort)
 				p.SetEndport(endPort)
 
-				r, err := cs.Firewall.CreateEgressFirewallRule(p)
+				sem.take()
+				r, err := jobs(cs).client().Firewall.CreateEgressFirewallRule(p)
+				sem.release()
+				if err == nil {
+					err = jobs(cs).wait(r.JobID)
+				}
 				if err != nil {
 					return err
 				}
@@ -270,16 +299,118 @@ func createEgressFirewallRule(d *schema.ResourceData, meta interface{}, rule map
 	}
 
 	if strings.ToLower(rule["protocol"].(string)) == "all" {
-		r, err := cs.Firewall.CreateEgressFirewallRule(p)
+		sem.take()
+		r, err := jobs(cs).client().Firewall.CreateEgressFirewallRule(p)
+		sem.release()
+		if err == nil {
+			err = jobs(cs).wait(r.JobID)
+		}
 		if err != nil {
 			return err
 		}
 		uuids["all"] = r.Id
 		rule["uuids"] = uuids
 	}
+
+	// Set the description and tags on all rules created for this rule
+	if tags := ruleTags(rule); len(tags) > 0 {
+		tp := cs.Resourcetags.NewCreateTagsParams(egressFirewallRuleIDs(rule), "FirewallRule", tags)
+		if _, err := cs.Resourcetags.CreateTags(tp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// egressFirewallRuleIDs returns the IDs of all firewall rules of a rule.
+func egressFirewallRuleIDs(rule map[string]interface{}) []string {
+	var ids []string
+	for k, id := range rule["uuids"].(map[string]interface{}) {
+		if k == "%" {
+			continue
+		}
+		ids = append(ids, id.(string))
+	}
+	return ids
+}
+
+// sameEgressFirewallRule reports whether two rules only differ in their
+// description and tags, so they can be updated without recreating them.
+func sameEgressFirewallRule(o, n map[string]interface{}) bool {
+	if o["protocol"].(string) != n["protocol"].(string) ||
+		o["icmp_type"].(int) != n["icmp_type"].(int) ||
+		o["icmp_code"].(int) != n["icmp_code"].(int) {
+		return false
+	}
+
+	for _, k := range []string{"cidr_list", "dest_cidr_list", "ports"} {
+		oset, ok := o[k].(*schema.Set)
+		if !ok {
+			oset = &schema.Set{F: schema.HashString}
+		}
+		nset, ok := n[k].(*schema.Set)
+		if !ok {
+			nset = &schema.Set{F: schema.HashString}
+		}
+		if oset.Len() != nset.Len() || oset.Difference(nset).Len() > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func updateEgressFirewallRuleTags(meta interface{}, rules *schema.Set, ors *schema.Set, nrs *schema.Set) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	for _, o := range ors.List() {
+		orule := o.(map[string]interface{})
+
+		for _, n := range nrs.List() {
+			nrule := n.(map[string]interface{})
+			if !sameEgressFirewallRule(orule, nrule) {
+				continue
+			}
+
+			ors.Remove(o)
+			nrs.Remove(n)
+
+			err := updateRuleTags(cs, egressFirewallRuleIDs(orule), "FirewallRule", orule, nrule)
+			if err != nil {
+				rules.Add(orule)
+				return err
+			}
+
+			nrule["uuids"] = orule["uuids"]
+			rules.Add(nrule)
+
+			break
+		}
+	}
+
 	return nil
 }
 
+// egressFirewallDefaultPolicy returns the default egress policy of a network,
+// which is defined by the egressdefaultpolicy of its network offering.
+func egressFirewallDefaultPolicy(cs *cloudstack.CloudStackClient, networkID string, project string) (string, error) {
+	n, _, err := cs.Network.GetNetworkByID(networkID, cloudstack.WithProject(project))
+	if err != nil {
+		return "", err
+	}
+
+	no, _, err := cs.NetworkOffering.GetNetworkOfferingByID(n.Networkofferingid)
+	if err != nil {
+		return "", err
+	}
+
+	if no.Egressdefaultpolicy {
+		return "allow", nil
+	}
+	return "deny", nil
+}
+
 // cidrSetFromList builds a schema.Set of CIDRs from a comma-separated list,
 // returning an empty set when the list is empty.
 func cidrSetFromList(list string) *schema.Set {
@@ -311,6 +442,13 @@ func resourceCloudStackEgressFirewallRead(d *schema.ResourceData, meta interface
 		return err
 	}
 
+	// Get the default egress policy of the network
+	policy, err := egressFirewallDefaultPolicy(cs, d.Id(), d.Get("project").(string))
+	if err != nil {
+		return err
+	}
+	d.Set("default_policy", policy)
+
 	// Make a map of all the rules so we can easily find a rule
 	ruleMap := make(map[string]*cloudstack.EgressFirewallRule, l.Count)
 	for _, r := range l.EgressFirewallRules {
@@ -351,6 +489,7 @@ func resourceCloudStackEgressFirewallRead(d *schema.ResourceData, meta interface
 				rule["icmp_code"] = r.Icmpcode
 				rule["cidr_list"] = cidrSetFromList(r.Cidrlist)
 				rule["dest_cidr_list"] = cidrSetFromList(r.Destcidrlist)
+				readRuleTags(rule, r.Tags)
 				rules.Add(rule)
 			case "all":
 				id, ok := uuids["all"]
@@ -372,6 +511,7 @@ func resourceCloudStackEgressFirewallRead(d *schema.ResourceData, meta interface
 				rule["protocol"] = r.Protocol
 				rule["cidr_list"] = cidrSetFromList(r.Cidrlist)
 				rule["dest_cidr_list"] = cidrSetFromList(r.Destcidrlist)
+				readRuleTags(rule, r.Tags)
 				rules.Add(rule)
 			default:
 				// Create an empty schema.Set to hold all ports
@@ -398,6 +538,7 @@ func resourceCloudStackEgressFirewallRead(d *schema.ResourceData, meta interface
 					rule["protocol"] = r.Protocol
 					rule["cidr_list"] = cidrSetFromList(r.Cidrlist)
 					rule["dest_cidr_list"] = cidrSetFromList(r.Destcidrlist)
+					readRuleTags(rule, r.Tags)
 					ports.Add(port)
 				}
 
@@ -437,6 +578,8 @@ func resourceCloudStackEgressFirewallRead(d *schema.ResourceData, meta interface
 		d.SetId("")
 	}
 
+	d.Set("policy_conflicts", egressPolicyConflicts(policy, d.Get("rule").(*schema.Set).List()))
+
 	return nil
 }
 
@@ -458,6 +601,15 @@ func resourceCloudStackEgressFirewallUpdate(d *schema.ResourceData, meta interfa
 		// set to make sure we end up in a consistent state
 		rules := o.(*schema.Set).Intersection(n.(*schema.Set))
 
+		// Rules of which only the description or tags changed are updated in place
+		if ors.Len() > 0 && nrs.Len() > 0 {
+			err := updateEgressFirewallRuleTags(meta, rules, ors, nrs)
+			if err != nil {
+				d.Set("rule", rules.Union(ors))
+				return err
+			}
+		}
+
 		// First loop through all the old rules and delete them
 		if ors.Len() > 0 {
 			err := deleteEgressFirewallRules(d, meta, rules, ors)
@@ -508,32 +660,31 @@ func resourceCloudStackEgressFirewallDelete(d *schema.ResourceData, meta interfa
 
 func deleteEgressFirewallRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, ors *schema.Set) error {
 	var errs *multierror.Error
+	var mu sync.Mutex
 
 	var wg sync.WaitGroup
 	wg.Add(ors.Len())
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
+	sem := make(jobSlots, d.Get("parallelism").(int))
 	for _, rule := range ors.List() {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Delete a single rule
-			err := deleteEgressFirewallRule(d, meta, rule)
+			err := deleteEgressFirewallRule(d, meta, sem, rule)
 
 			// If we have at least one UUID, we need to save the rule
 			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				mu.Lock()
 				rules.Add(rule)
+				mu.Unlock()
 			}
 
 			if err != nil {
-				errs = multierror.Append(errs, err)
+				mu.Lock()
+				errs = multierror.Append(errs, ruleError("deleting", rule, err))
+				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -542,7 +693,7 @@ func deleteEgressFirewallRules(d *schema.ResourceData, meta interface{}, rules *
 	return errs.ErrorOrNil()
 }
 
-func deleteEgressFirewallRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
+func deleteEgressFirewallRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 	uuids := rule["uuids"].(map[string]interface{})
 
@@ -556,7 +707,13 @@ func deleteEgressFirewallRule(d *schema.ResourceData, meta interface{}, rule map
 		p := cs.Firewall.NewDeleteEgressFirewallRuleParams(id.(string))
 
 		// Delete the rule
-		if _, err := cs.Firewall.DeleteEgressFirewallRule(p); err != nil {
+		sem.take()
+		r, err := jobs(cs).client().Firewall.DeleteEgressFirewallRule(p)
+		sem.release()
+		if err == nil {
+			err = jobs(cs).wait(r.JobID)
+		}
+		if err != nil {
 
 			// This is a very poor way to be told the ID does no longer exist :(
 			if strings.Contains(err.Error(), fmt.Sprintf(
@@ -577,6 +734,238 @@ func deleteEgressFirewallRule(d *schema.ResourceData, meta interface{}, rule map
 	return nil
 }
 
+func resourceCloudStackEgressFirewallCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Reject duplicate and overlapping rules before any of them are created
+	if portRulesKnown(d, "rule") {
+		var rules []portRule
+		for _, rule := range d.Get("rule").(*schema.Set).List() {
+			rule := rule.(map[string]interface{})
+
+			var suffix string
+			if dest := setToStrings(rule["dest_cidr_list"]); len(dest) > 0 {
+				suffix = "to " + strings.Join(dest, ",")
+			}
+
+			rules = append(rules, expandPortRules(rule, "", suffix, setToStrings(rule["cidr_list"]))...)
+		}
+
+		if err := checkPortRules(portAliasesOf(cs), rules, false); err != nil {
+			return err
+		}
+	}
+
+	// We can only look up the policy of networks that already exist
+	networkID := d.Get("network_id").(string)
+	if networkID == "" || !d.NewValueKnown("network_id") {
+		return nil
+	}
+
+	policy, err := egressFirewallDefaultPolicy(cs, networkID, d.Get("project").(string))
+	if err != nil {
+		return err
+	}
+
+	// The default policy can only be changed by changing the network offering
+	if d.HasChange("default_policy") {
+		if v := d.Get("default_policy").(string); v != "" && v != policy {
+			return fmt.Errorf(
+				"default_policy %q does not match the default egress policy %q of network %s, "+
+					"which is defined by its network offering", v, policy, networkID)
+		}
+	}
+
+	if d.Get("default_policy").(string) != policy {
+		if err := d.SetNew("default_policy", policy); err != nil {
+			return err
+		}
+	}
+
+	// Show the rules that conflict with the default policy in the plan, as
+	// warnings can't be returned when planning
+	if !d.NewValueKnown("rule") {
+		return d.SetNewComputed("policy_conflicts")
+	}
+
+	conflicts := egressPolicyConflicts(policy, d.Get("rule").(*schema.Set).List())
+	if strings.Join(conflicts, "\n") != strings.Join(setToStrings(d.Get("policy_conflicts")), "\n") {
+		if err := d.SetNew("policy_conflicts", conflicts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// warnEgressPolicyConflicts wraps a function of the egress firewall resource
+// to return a warning for every rule in policy_conflicts. When all egress
+// traffic is allowed by default, every rule blocks the matching traffic
+// instead of allowing it.
+func warnEgressPolicyConflicts(f func(*schema.ResourceData, interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if err := f(d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if d.Id() == "" {
+			return nil
+		}
+
+		var diags diag.Diagnostics
+		for _, rule := range d.Get("policy_conflicts").([]interface{}) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Egress firewall rule conflicts with the default policy of %s", d.Id()),
+				Detail: fmt.Sprintf(
+					"The network allows all egress traffic by default, so the rule (%s) blocks the "+
+						"matching egress traffic instead of allowing it.", rule),
+			})
+		}
+
+		return diags
+	}
+}
+
+// egressPolicyConflicts returns a sorted description of every rule that
+// conflicts with the given default policy. When the policy is "allow",
+// every rule blocks the matching traffic instead of allowing it.
+func egressPolicyConflicts(policy string, rules []interface{}) []string {
+	if policy != "allow" {
+		return nil
+	}
+
+	var conflicts []string
+	for _, rule := range rules {
+		rule := rule.(map[string]interface{})
+
+		switch strings.ToLower(rule["protocol"].(string)) {
+		case "tcp", "udp", "icmp", "all":
+			conflicts = append(conflicts, describeEgressRule(rule))
+		}
+	}
+	sort.Strings(conflicts)
+
+	return conflicts
+}
+
+// describeEgressRule returns a short description of an egress rule, e.g.
+// "tcp port 80, 443 from 10.0.0.0/24 to 0.0.0.0/0".
+func describeEgressRule(rule map[string]interface{}) string {
+	protocol := strings.ToLower(rule["protocol"].(string))
+	parts := []string{protocol}
+
+	switch protocol {
+	case "icmp":
+		parts = append(parts, fmt.Sprintf("type %v code %v", rule["icmp_type"], rule["icmp_code"]))
+	case "tcp", "udp":
+		if ports := setToStrings(rule["ports"]); len(ports) > 0 {
+			parts = append(parts, "port "+strings.Join(ports, ", "))
+		}
+	}
+
+	if cidrs := setToStrings(rule["cidr_list"]); len(cidrs) > 0 {
+		parts = append(parts, "from "+strings.Join(cidrs, ", "))
+	}
+
+	if dest := setToStrings(rule["dest_cidr_list"]); len(dest) > 0 {
+		parts = append(parts, "to "+strings.Join(dest, ", "))
+	} else {
+		parts = append(parts, "to all destinations")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func resourceCloudStackEgressFirewallImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Try to split the ID to extract the optional project name.
+	s := strings.SplitN(d.Id(), "/", 2)
+	if len(s) == 2 {
+		d.Set("project", s[0])
+	}
+
+	networkID := s[len(s)-1]
+	d.SetId(networkID)
+	d.Set("network_id", networkID)
+
+	// Get all the rules configured for this network
+	p := cs.Firewall.NewListEgressFirewallRulesParams()
+	p.SetNetworkid(networkID)
+	p.SetListall(true)
+
+	if err := setProjectid(p, cs, d); err != nil {
+		return nil, err
+	}
+
+	l, err := cs.Firewall.ListEgressFirewallRules(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create an empty schema.Set to hold all rules
+	rules := resourceCloudStackEgressFirewall().Schema["rule"].ZeroValue().(*schema.Set)
+
+	// Group the TCP and UDP rules by protocol and CIDR lists
+	grouped := newImportedRules()
+
+	for _, r := range l.EgressFirewallRules {
+		protocol := strings.ToLower(r.Protocol)
+
+		switch protocol {
+		case "icmp":
+			rule := map[string]interface{}{
+				"cidr_list":      cidrSetFromList(r.Cidrlist),
+				"dest_cidr_list": cidrSetFromList(r.Destcidrlist),
+				"protocol":       r.Protocol,
+				"icmp_type":      r.Icmptype,
+				"icmp_code":      r.Icmpcode,
+				"ports":          &schema.Set{F: schema.HashString},
+				"uuids":          map[string]interface{}{"icmp": r.Id},
+			}
+			readRuleTags(rule, r.Tags)
+			rules.Add(rule)
+		case "all":
+			rule := map[string]interface{}{
+				"cidr_list":      cidrSetFromList(r.Cidrlist),
+				"dest_cidr_list": cidrSetFromList(r.Destcidrlist),
+				"protocol":       r.Protocol,
+				"ports":          &schema.Set{F: schema.HashString},
+				"uuids":          map[string]interface{}{"all": r.Id},
+			}
+			readRuleTags(rule, r.Tags)
+			rules.Add(rule)
+		default:
+			// Rules with different descriptions or tags can't share a rule block
+			key := fmt.Sprintf("%s/%s/%s/%v", protocol,
+				sortedCIDRList(r.Cidrlist), sortedCIDRList(r.Destcidrlist), tagsToMap(r.Tags))
+
+			rule := grouped.group(key, func() map[string]interface{} {
+				rule := map[string]interface{}{
+					"cidr_list":      cidrSetFromList(r.Cidrlist),
+					"dest_cidr_list": cidrSetFromList(r.Destcidrlist),
+					"protocol":       r.Protocol,
+				}
+				readRuleTags(rule, r.Tags)
+				return rule
+			})
+			grouped.addPort(rule, "", r.Startport, r.Endport, r.Id)
+		}
+	}
+
+	for _, rule := range grouped.list() {
+		rules.Add(rule)
+	}
+
+	d.Set("rule", rules)
+
+	d.Set("managed", false)
+	d.Set("parallelism", 2)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func verifyEgressFirewallParams(d *schema.ResourceData) error {
 	managed := d.Get("managed").(bool)
 	_, rules := d.GetOk("rule")
@@ -589,7 +978,14 @@ func verifyEgressFirewallParams(d *schema.ResourceData) error {
 	return nil
 }
 
-func verifyEgressFirewallRuleParams(d *schema.ResourceData, rule map[string]interface{}) error {
+func verifyEgressFirewallRuleParams(d *schema.ResourceData, pa portAliases, rule map[string]interface{}) error {
+	if tags, ok := rule["tags"].(map[string]interface{}); ok {
+		if _, ok := tags["description"]; ok {
+			return fmt.Errorf(
+				"The 'description' tag is reserved, use the description parameter instead")
+		}
+	}
+
 	protocol := rule["protocol"].(string)
 	if strings.ToLower(protocol) != "all" && protocol != "tcp" && protocol != "udp" && protocol != "icmp" {
 		return fmt.Errorf(
@@ -608,10 +1004,8 @@ func verifyEgressFirewallRuleParams(d *schema.ResourceData, rule map[string]inte
 	} else if strings.ToLower(protocol) != "all" {
 		if ports, ok := rule["ports"].(*schema.Set); ok {
 			for _, port := range ports.List() {
-				m := splitPorts.FindStringSubmatch(port.(string))
-				if m == nil {
-					return fmt.Errorf(
-						"%q is not a valid port value. Valid options are '80' or '80-90'", port.(string))
+				if _, _, err := pa.parsePortRange(port.(string)); err != nil {
+					return err
 				}
 			}
 		} else {
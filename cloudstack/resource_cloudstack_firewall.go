@@ -20,11 +20,10 @@
 package cloudstack
 
 import (
+	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/go-multierror"
@@ -32,11 +31,15 @@ import (
 )
 
 func resourceCloudStackFirewall() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceCloudStackFirewallCreate,
-		Read:   resourceCloudStackFirewallRead,
-		Update: resourceCloudStackFirewallUpdate,
-		Delete: resourceCloudStackFirewallDelete,
+	r := &schema.Resource{
+		Create:      resourceCloudStackFirewallCreate,
+		ReadContext: readReportingUnmanagedRules(resourceCloudStackFirewallRead),
+		Update:      resourceCloudStackFirewallUpdate,
+		Delete:      resourceCloudStackFirewallDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackFirewallImport,
+		},
+		CustomizeDiff: firewallCustomizeDiff(firewallPortRules),
 
 		Schema: map[string]*schema.Schema{
 			"ip_address_id": {
@@ -52,11 +55,9 @@ func resourceCloudStackFirewall() *schema.Resource {
 				ForceNew: true,
 			},
 
-			"managed": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
+			"managed": managedSchema(),
+
+			"unmanaged_rules": unmanagedRulesSchema(),
 
 			"rule": {
 				Type:     schema.TypeSet,
@@ -109,6 +110,12 @@ func resourceCloudStackFirewall() *schema.Resource {
 			},
 		},
 	}
+
+	// The managed field was a bool before it also accepted "report"
+	r.SchemaVersion = 1
+	r.StateUpgraders = []schema.StateUpgrader{managedStateUpgradeV0(r)}
+
+	return r
 }
 
 func resourceCloudStackFirewallCreate(d *schema.ResourceData, meta interface{}) error {
@@ -138,33 +145,43 @@ func resourceCloudStackFirewallCreate(d *schema.ResourceData, meta interface{})
 	return resourceCloudStackFirewallRead(d, meta)
 }
 func createFirewallRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, nrs *schema.Set) error {
+	return applyFirewallRules(d, rules, nrs, "creating", func(sem jobSlots, rule map[string]interface{}) error {
+		return createFirewallRule(d, meta, sem, rule)
+	})
+}
+
+// applyFirewallRules concurrently calls apply for all rules in rs, sharing
+// as many job slots as the configured parallelism. Rules that still have at
+// least one UUID afterwards are added to rules.
+func applyFirewallRules(
+	d *schema.ResourceData, rules *schema.Set, rs *schema.Set, action string,
+	apply func(sem jobSlots, rule map[string]interface{}) error) error {
 	var errs *multierror.Error
+	var mu sync.Mutex
 
 	var wg sync.WaitGroup
-	wg.Add(nrs.Len())
-
-	sem := make(chan struct{}, d.Get("parallelism").(int))
-	for _, rule := range nrs.List() {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
+	wg.Add(rs.Len())
 
+	sem := make(jobSlots, d.Get("parallelism").(int))
+	for _, rule := range rs.List() {
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
-			// Create a single rule
-			err := createFirewallRule(d, meta, rule)
+			// Apply a single rule
+			err := apply(sem, rule)
 
 			// If we have at least one UUID, we need to save the rule
 			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				mu.Lock()
 				rules.Add(rule)
+				mu.Unlock()
 			}
 
 			if err != nil {
-				errs = multierror.Append(errs, err)
+				mu.Lock()
+				errs = multierror.Append(errs, ruleError(action, rule, err))
+				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -173,12 +190,11 @@ func createFirewallRules(d *schema.ResourceData, meta interface{}, rules *schema
 	return errs.ErrorOrNil()
 }
 
-func createFirewallRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
+func createFirewallRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
-	uuids := rule["uuids"].(map[string]interface{})
 
 	// Make sure all required rule parameters are there
-	if err := verifyFirewallRuleParams(d, rule); err != nil {
+	if err := verifyFirewallRuleParams(d, portAliasesOf(cs), rule); err != nil {
 		return err
 	}
 
@@ -186,69 +202,81 @@ func createFirewallRule(d *schema.ResourceData, meta interface{}, rule map[strin
 	p := cs.Firewall.NewCreateFirewallRuleParams(d.Id(), rule["protocol"].(string))
 
 	// Set the CIDR list
-	var cidrList []string
-	for _, cidr := range rule["cidr_list"].(*schema.Set).List() {
-		cidrList = append(cidrList, cidr.(string))
-	}
-	p.SetCidrlist(cidrList)
+	p.SetCidrlist(setToStrings(rule["cidr_list"]))
 
 	// If the protocol is ICMP set the needed ICMP parameters
 	if rule["protocol"].(string) == "icmp" {
 		p.SetIcmptype(rule["icmp_type"].(int))
 		p.SetIcmpcode(rule["icmp_code"].(int))
-
-		r, err := cs.Firewall.CreateFirewallRule(p)
-		if err != nil {
-			return err
-		}
-
-		uuids["icmp"] = r.Id
-		rule["uuids"] = uuids
 	}
 
-	// If protocol is not ICMP, loop through all ports
-	if rule["protocol"].(string) != "icmp" {
-		if ps := rule["ports"].(*schema.Set); ps.Len() > 0 {
-
-			// Create an empty schema.Set to hold all processed ports
-			ports := &schema.Set{F: schema.HashString}
+	return createFirewallRuleIDs(portAliasesOf(cs), rule, func(startPort, endPort int) (string, error) {
+		if startPort != 0 {
+			p.SetStartport(startPort)
+			p.SetEndport(endPort)
+		}
 
-			for _, port := range ps.List() {
-				if _, ok := uuids[port.(string)]; ok {
-					ports.Add(port)
-					rule["ports"] = ports
-					continue
-				}
+		sem.take()
+		r, err := jobs(cs).client().Firewall.CreateFirewallRule(p)
+		sem.release()
+		if err == nil {
+			err = jobs(cs).wait(r.JobID)
+		}
+		if err != nil {
+			return "", err
+		}
 
-				m := splitPorts.FindStringSubmatch(port.(string))
+		return r.Id, nil
+	})
+}
 
-				startPort, err := strconv.Atoi(m[1])
-				if err != nil {
-					return err
-				}
+// createFirewallRuleIDs calls create for every port of the rule that has no
+// UUID yet, or once if the protocol is ICMP or "all", and stores the UUIDs of
+// the created rules in the rule. The ports passed to create are zero for ICMP
+// and "all" rules.
+func createFirewallRuleIDs(pa portAliases, rule map[string]interface{}, create func(startPort, endPort int) (string, error)) error {
+	uuids := rule["uuids"].(map[string]interface{})
 
-				endPort := startPort
-				if m[2] != "" {
-					endPort, err = strconv.Atoi(m[2])
-					if err != nil {
-						return err
-					}
-				}
+	switch protocol := strings.ToLower(rule["protocol"].(string)); protocol {
+	case "icmp", "all":
+		id, err := create(0, 0)
+		if err != nil {
+			return err
+		}
 
-				p.SetStartport(startPort)
-				p.SetEndport(endPort)
+		uuids[protocol] = id
+		rule["uuids"] = uuids
+	default:
+		ps, ok := rule["ports"].(*schema.Set)
+		if !ok || ps.Len() == 0 {
+			return nil
+		}
 
-				r, err := cs.Firewall.CreateFirewallRule(p)
-				if err != nil {
-					return err
-				}
+		// Create an empty schema.Set to hold all processed ports
+		ports := &schema.Set{F: schema.HashString}
 
+		for _, port := range ps.List() {
+			if _, ok := uuids[port.(string)]; ok {
 				ports.Add(port)
 				rule["ports"] = ports
+				continue
+			}
+
+			startPort, endPort, err := pa.parsePortRange(port.(string))
+			if err != nil {
+				return err
+			}
 
-				uuids[port.(string)] = r.Id
-				rule["uuids"] = uuids
+			id, err := create(startPort, endPort)
+			if err != nil {
+				return err
 			}
+
+			ports.Add(port)
+			rule["ports"] = ports
+
+			uuids[port.(string)] = id
+			rule["uuids"] = uuids
 		}
 	}
 
@@ -283,91 +311,98 @@ func resourceCloudStackFirewallRead(d *schema.ResourceData, meta interface{}) er
 	rules := resourceCloudStackFirewall().Schema["rule"].ZeroValue().(*schema.Set)
 
 	// Read all rules that are configured
-	if rs := d.Get("rule").(*schema.Set); rs.Len() > 0 {
-		for _, rule := range rs.List() {
-			rule := rule.(map[string]interface{})
-			uuids := rule["uuids"].(map[string]interface{})
+	readFirewallRules(d.Get("rule").(*schema.Set), rules, func(rule map[string]interface{}, key, id string) bool {
+		// Get the rule
+		r, ok := ruleMap[id]
+		if !ok {
+			return false
+		}
 
-			if rule["protocol"].(string) == "icmp" {
-				id, ok := uuids["icmp"]
-				if !ok {
-					continue
-				}
+		// Delete the known rule so only unknown rules remain in the ruleMap
+		delete(ruleMap, id)
+
+		// Update the values
+		rule["protocol"] = r.Protocol
+		rule["cidr_list"] = cidrSetFromList(r.Cidrlist)
+		if key == "icmp" {
+			rule["icmp_type"] = r.Icmptype
+			rule["icmp_code"] = r.Icmpcode
+		}
+
+		return true
+	})
 
-				// Get the rule
-				r, ok := ruleMap[id.(string)]
+	unknown := make(map[string]map[string]interface{}, len(ruleMap))
+	for uuid, r := range ruleMap {
+		unknown[uuid] = firewallUnmanagedRule(r)
+	}
+
+	return setFirewallRules(d, rules, unknown)
+}
+
+// readFirewallRules adds the configured rules in rs that still exist to
+// rules. The read function is called for every UUID of a rule, with the
+// protocol or port the UUID belongs to. It updates the values of the rule
+// and returns false if the rule no longer exists.
+func readFirewallRules(rs *schema.Set, rules *schema.Set, read func(rule map[string]interface{}, key, id string) bool) {
+	for _, rule := range rs.List() {
+		rule := rule.(map[string]interface{})
+		uuids := rule["uuids"].(map[string]interface{})
+
+		switch key := strings.ToLower(rule["protocol"].(string)); key {
+		case "icmp", "all":
+			id, ok := uuids[key]
+			if !ok {
+				continue
+			}
+
+			if !read(rule, key, id.(string)) {
+				delete(uuids, key)
+				continue
+			}
+
+			rules.Add(rule)
+		default:
+			ps, ok := rule["ports"].(*schema.Set)
+			if !ok || ps.Len() == 0 {
+				continue
+			}
+
+			// Create an empty schema.Set to hold all ports
+			ports := &schema.Set{F: schema.HashString}
+
+			// Loop through all ports and retrieve their info
+			for _, port := range ps.List() {
+				id, ok := uuids[port.(string)]
 				if !ok {
-					delete(uuids, "icmp")
 					continue
 				}
 
-				// Delete the known rule so only unknown rules remain in the ruleMap
-				delete(ruleMap, id.(string))
-
-				// Create a set with all CIDR's
-				cidrs := &schema.Set{F: schema.HashString}
-				for _, cidr := range strings.Split(r.Cidrlist, ",") {
-					cidrs.Add(cidr)
+				if !read(rule, port.(string), id.(string)) {
+					delete(uuids, port.(string))
+					continue
 				}
 
-				// Update the values
-				rule["protocol"] = r.Protocol
-				rule["icmp_type"] = r.Icmptype
-				rule["icmp_code"] = r.Icmpcode
-				rule["cidr_list"] = cidrs
-				rules.Add(rule)
+				ports.Add(port)
 			}
 
-			// If protocol is not ICMP, loop through all ports
-			if rule["protocol"].(string) != "icmp" {
-				if ps := rule["ports"].(*schema.Set); ps.Len() > 0 {
-
-					// Create an empty schema.Set to hold all ports
-					ports := &schema.Set{F: schema.HashString}
-
-					// Loop through all ports and retrieve their info
-					for _, port := range ps.List() {
-						id, ok := uuids[port.(string)]
-						if !ok {
-							continue
-						}
-
-						// Get the rule
-						r, ok := ruleMap[id.(string)]
-						if !ok {
-							delete(uuids, port.(string))
-							continue
-						}
-
-						// Delete the known rule so only unknown rules remain in the ruleMap
-						delete(ruleMap, id.(string))
-
-						// Create a set with all CIDR's
-						cidrs := &schema.Set{F: schema.HashString}
-						for _, cidr := range strings.Split(r.Cidrlist, ",") {
-							cidrs.Add(cidr)
-						}
-
-						// Update the values
-						rule["protocol"] = r.Protocol
-						rule["cidr_list"] = cidrs
-						ports.Add(port)
-					}
-
-					// If there is at least one port found, add this rule to the rules set
-					if ports.Len() > 0 {
-						rule["ports"] = ports
-						rules.Add(rule)
-					}
-				}
+			// If there is at least one port found, add this rule to the rules set
+			if ports.Len() > 0 {
+				rule["ports"] = ports
+				rules.Add(rule)
 			}
 		}
 	}
+}
 
-	// If this is a managed firewall, add all unknown rules into a single dummy rule
-	managed := d.Get("managed").(bool)
-	if managed && len(ruleMap) > 0 {
-		for uuid := range ruleMap {
+// setFirewallRules sets the rules that are read, together with the rules that
+// exist but are not configured, given as unmanaged rules by UUID. If this is
+// a managed firewall, the unknown rules are added as dummy rules so they will
+// be deleted. If they are only reported, they are set as unmanaged rules.
+func setFirewallRules(d *schema.ResourceData, rules *schema.Set, unknown map[string]map[string]interface{}) error {
+	managed := d.Get("managed").(string)
+	if managed == managedTrue {
+		for uuid := range unknown {
 			// We need to create and add a dummy value to a schema.Set as the
 			// cidr_list is a required field and thus needs a value
 			cidrs := &schema.Set{F: schema.HashString}
@@ -385,9 +420,20 @@ func resourceCloudStackFirewallRead(d *schema.ResourceData, meta interface{}) er
 		}
 	}
 
+	// If unknown rules are only reported, add them to the unmanaged rules
+	var unmanaged []map[string]interface{}
+	if managed == managedReport {
+		for _, rule := range unknown {
+			unmanaged = append(unmanaged, rule)
+		}
+	}
+	if err := setUnmanagedRules(d, unmanaged); err != nil {
+		return err
+	}
+
 	if rules.Len() > 0 {
 		d.Set("rule", rules)
-	} else if !managed {
+	} else if managed == managedFalse {
 		d.SetId("")
 	}
 
@@ -461,43 +507,33 @@ func resourceCloudStackFirewallDelete(d *schema.ResourceData, meta interface{})
 }
 
 func deleteFirewallRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, ors *schema.Set) error {
-	var errs *multierror.Error
-
-	var wg sync.WaitGroup
-	wg.Add(ors.Len())
-
-	sem := make(chan struct{}, d.Get("parallelism").(int))
-	for _, rule := range ors.List() {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
-		go func(rule map[string]interface{}) {
-			defer wg.Done()
-			sem <- struct{}{}
-
-			// Delete a single rule
-			err := deleteFirewallRule(d, meta, rule)
-
-			// If we have at least one UUID, we need to save the rule
-			if len(rule["uuids"].(map[string]interface{})) > 0 {
-				rules.Add(rule)
-			}
+	return applyFirewallRules(d, rules, ors, "deleting", func(sem jobSlots, rule map[string]interface{}) error {
+		return deleteFirewallRule(d, meta, sem, rule)
+	})
+}
 
-			if err != nil {
-				errs = multierror.Append(errs, err)
-			}
+func deleteFirewallRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
 
-			<-sem
-		}(rule.(map[string]interface{}))
-	}
+	return deleteFirewallRuleIDs(rule, func(id string) error {
+		// Create the parameter struct
+		p := cs.Firewall.NewDeleteFirewallRuleParams(id)
 
-	wg.Wait()
+		// Delete the rule
+		sem.take()
+		r, err := jobs(cs).client().Firewall.DeleteFirewallRule(p)
+		sem.release()
+		if err == nil {
+			err = jobs(cs).wait(r.JobID)
+		}
 
-	return errs.ErrorOrNil()
+		return err
+	})
 }
 
-func deleteFirewallRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+// deleteFirewallRuleIDs calls del for every UUID of the rule and removes the
+// UUIDs of the deleted rules, or rules that no longer exist, from the rule.
+func deleteFirewallRuleIDs(rule map[string]interface{}, del func(id string) error) error {
 	uuids := rule["uuids"].(map[string]interface{})
 
 	for k, id := range uuids {
@@ -506,11 +542,8 @@ func deleteFirewallRule(d *schema.ResourceData, meta interface{}, rule map[strin
 			continue
 		}
 
-		// Create the parameter struct
-		p := cs.Firewall.NewDeleteFirewallRuleParams(id.(string))
-
 		// Delete the rule
-		if _, err := cs.Firewall.DeleteFirewallRule(p); err != nil {
+		if err := del(id.(string)); err != nil {
 
 			// This is a very poor way to be told the ID does no longer exist :(
 			if strings.Contains(err.Error(), fmt.Sprintf(
@@ -531,8 +564,99 @@ func deleteFirewallRule(d *schema.ResourceData, meta interface{}, rule map[strin
 	return nil
 }
 
+// firewallCustomizeDiff returns a CustomizeDiffFunc that rejects duplicate
+// and overlapping rules before any of them are created, using portRules to
+// expand every rule into port rules.
+func firewallCustomizeDiff(portRules func(rule map[string]interface{}) []portRule) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if !portRulesKnown(d, "rule") {
+			return nil
+		}
+
+		var rules []portRule
+		for _, rule := range d.Get("rule").(*schema.Set).List() {
+			rules = append(rules, portRules(rule.(map[string]interface{}))...)
+		}
+
+		return checkPortRules(portAliasesOf(meta.(*cloudstack.CloudStackClient)), rules, false)
+	}
+}
+
+func firewallPortRules(rule map[string]interface{}) []portRule {
+	return expandPortRules(rule, "", "", setToStrings(rule["cidr_list"]))
+}
+
+func resourceCloudStackFirewallImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Try to split the ID to extract the optional project name.
+	s := strings.SplitN(d.Id(), "/", 2)
+	if len(s) == 2 {
+		d.Set("project", s[0])
+	}
+
+	ipAddressID := s[len(s)-1]
+	d.SetId(ipAddressID)
+	d.Set("ip_address_id", ipAddressID)
+
+	// Get all the rules configured for this IP address
+	p := cs.Firewall.NewListFirewallRulesParams()
+	p.SetIpaddressid(ipAddressID)
+	p.SetListall(true)
+
+	if err := setProjectid(p, cs, d); err != nil {
+		return nil, err
+	}
+
+	l, err := cs.Firewall.ListFirewallRules(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create an empty schema.Set to hold all rules
+	rules := resourceCloudStackFirewall().Schema["rule"].ZeroValue().(*schema.Set)
+
+	// Group the TCP and UDP rules by protocol and CIDR list
+	grouped := newImportedRules()
+
+	for _, r := range l.FirewallRules {
+		if r.Protocol == "icmp" {
+			rules.Add(map[string]interface{}{
+				"cidr_list": cidrSetFromList(r.Cidrlist),
+				"protocol":  r.Protocol,
+				"icmp_type": r.Icmptype,
+				"icmp_code": r.Icmpcode,
+				"ports":     &schema.Set{F: schema.HashString},
+				"uuids":     map[string]interface{}{"icmp": r.Id},
+			})
+			continue
+		}
+
+		rule := grouped.group(r.Protocol+"/"+sortedCIDRList(r.Cidrlist), func() map[string]interface{} {
+			return map[string]interface{}{
+				"cidr_list": cidrSetFromList(r.Cidrlist),
+				"protocol":  r.Protocol,
+			}
+		})
+		grouped.addPort(rule, "", r.Startport, r.Endport, r.Id)
+	}
+
+	for _, rule := range grouped.list() {
+		rules.Add(rule)
+	}
+
+	d.Set("rule", rules)
+
+	// Don't delete rules that are added to the IP address later on, unless
+	// the config says otherwise
+	d.Set("managed", managedFalse)
+	d.Set("parallelism", 2)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func verifyFirewallParams(d *schema.ResourceData) error {
-	managed := d.Get("managed").(bool)
+	managed := d.Get("managed").(string) != managedFalse
 	_, rules := d.GetOk("rule")
 
 	if !rules && !managed {
@@ -543,7 +667,7 @@ func verifyFirewallParams(d *schema.ResourceData) error {
 	return nil
 }
 
-func verifyFirewallRuleParams(d *schema.ResourceData, rule map[string]interface{}) error {
+func verifyFirewallRuleParams(d *schema.ResourceData, pa portAliases, rule map[string]interface{}) error {
 	protocol := rule["protocol"].(string)
 	if protocol != "tcp" && protocol != "udp" && protocol != "icmp" {
 		return fmt.Errorf(
@@ -562,10 +686,8 @@ func verifyFirewallRuleParams(d *schema.ResourceData, rule map[string]interface{
 	} else {
 		if ports, ok := rule["ports"].(*schema.Set); ok {
 			for _, port := range ports.List() {
-				m := splitPorts.FindStringSubmatch(port.(string))
-				if m == nil {
-					return fmt.Errorf(
-						"%q is not a valid port value. Valid options are '80' or '80-90'", port.(string))
+				if _, _, err := pa.parsePortRange(port.(string)); err != nil {
+					return err
 				}
 			}
 		} else {
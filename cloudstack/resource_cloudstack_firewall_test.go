@@ -126,6 +126,25 @@ func TestAccCloudStackFirewall_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackFirewall_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackFirewall_update,
+			},
+
+			{
+				ResourceName:      "cloudstack_firewall.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckCloudStackFirewallRulesExist(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
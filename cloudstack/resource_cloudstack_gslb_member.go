@@ -0,0 +1,160 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudStackGSLBMember() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackGSLBMemberCreate,
+		Read:   resourceCloudStackGSLBMemberRead,
+		Update: resourceCloudStackGSLBMemberUpdate,
+		Delete: resourceCloudStackGSLBMemberDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackGSLBMemberImport,
+		},
+		// Changing the weight assigns the load balancer rule again, unless
+		// it is unknown because the member was imported
+		CustomizeDiff: forceNewUnlessUnset("weight"),
+
+		Schema: map[string]*schema.Schema{
+			"gslb_rule_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"loadbalancer_rule_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"weight": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+		},
+	}
+}
+
+func resourceCloudStackGSLBMemberCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	gslbruleid := d.Get("gslb_rule_id").(string)
+	lbruleid := d.Get("loadbalancer_rule_id").(string)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewAssignToGlobalLoadBalancerRuleParams(gslbruleid, []string{lbruleid})
+	p.SetGslblbruleweightsmap(map[string]string{
+		lbruleid: strconv.Itoa(d.Get("weight").(int)),
+	})
+
+	_, err := cs.LoadBalancer.AssignToGlobalLoadBalancerRule(p)
+	if err != nil {
+		return fmt.Errorf(
+			"Error assigning load balancer rule %s to GSLB rule %s: %s", lbruleid, gslbruleid, err)
+	}
+
+	d.SetId(lbruleid)
+
+	return resourceCloudStackGSLBMemberRead(d, meta)
+}
+
+func resourceCloudStackGSLBMemberRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the GSLB rule details
+	r, count, err := cs.LoadBalancer.GetGlobalLoadBalancerRuleByID(d.Get("gslb_rule_id").(string))
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] GSLB rule %s does no longer exist", d.Get("gslb_rule_id").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	for _, lb := range r.Loadbalancerrule {
+		if lb.Id == d.Id() {
+			d.Set("loadbalancer_rule_id", lb.Id)
+			return nil
+		}
+	}
+
+	log.Printf("[DEBUG] Load balancer rule %s is no longer assigned to GSLB rule %s", d.Id(), r.Name)
+	d.SetId("")
+
+	return nil
+}
+
+func resourceCloudStackGSLBMemberUpdate(d *schema.ResourceData, meta interface{}) error {
+	// The only in place update stores the weight of an imported member,
+	// which is not sent to CloudStack
+	return resourceCloudStackGSLBMemberRead(d, meta)
+}
+
+func resourceCloudStackGSLBMemberDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewRemoveFromGlobalLoadBalancerRuleParams(
+		d.Get("gslb_rule_id").(string), []string{d.Id()})
+
+	if _, err := cs.LoadBalancer.RemoveFromGlobalLoadBalancerRule(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Get("gslb_rule_id").(string))) {
+			return nil
+		}
+
+		return fmt.Errorf(
+			"Error removing load balancer rule %s from GSLB rule %s: %s",
+			d.Id(), d.Get("gslb_rule_id").(string), err)
+	}
+
+	return nil
+}
+
+func resourceCloudStackGSLBMemberImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	s := strings.SplitN(d.Id(), "/", 2)
+	if len(s) != 2 {
+		return nil, fmt.Errorf(
+			"Invalid import ID %q, expected <GSLB RULE ID>/<LOAD BALANCER RULE ID>", d.Id())
+	}
+
+	d.Set("gslb_rule_id", s[0])
+	d.SetId(s[1])
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,258 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackGSLBMember_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackGSLBMemberDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackGSLBMember_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackGSLBMemberExists("cloudstack_gslb_member.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_gslb_member.foo", "weight", "1"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackGSLBMember_update,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("cloudstack_gslb_member.foo", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackGSLBMemberExists("cloudstack_gslb_member.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_gslb_member.foo", "weight", "50"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackGSLBMember_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackGSLBMemberDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackGSLBMember_update,
+			},
+
+			{
+				ResourceName:      "cloudstack_gslb_member.foo",
+				ImportState:       true,
+				ImportStateIdFunc: testAccCloudStackGSLBMemberImportID("cloudstack_gslb_member.foo"),
+				ImportStateVerify: true,
+				// The weight can't be read back from the CloudStack API
+				ImportStateVerifyIgnore: []string{"weight"},
+			},
+
+			{
+				ResourceName:       "cloudstack_gslb_member.foo",
+				ImportState:        true,
+				ImportStateIdFunc:  testAccCloudStackGSLBMemberImportID("cloudstack_gslb_member.foo"),
+				ImportStatePersist: true,
+			},
+
+			{
+				// The unknown weight of an imported member is stored in place
+				Config: testAccCloudStackGSLBMember_update,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("cloudstack_gslb_member.foo", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(
+					"cloudstack_gslb_member.foo", "weight", "50"),
+			},
+		},
+	})
+}
+
+func testAccCloudStackGSLBMemberImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+
+		return fmt.Sprintf("%s/%s", rs.Primary.Attributes["gslb_rule_id"], rs.Primary.ID), nil
+	}
+}
+
+func testAccCheckCloudStackGSLBMemberExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No GSLB member ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		r, _, err := cs.LoadBalancer.GetGlobalLoadBalancerRuleByID(rs.Primary.Attributes["gslb_rule_id"])
+		if err != nil {
+			return err
+		}
+
+		for _, lb := range r.Loadbalancerrule {
+			if lb.Id == rs.Primary.ID {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("GSLB member not found")
+	}
+}
+
+func testAccCheckCloudStackGSLBMemberDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_gslb_member" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No GSLB member ID is set")
+		}
+
+		r, _, err := cs.LoadBalancer.GetGlobalLoadBalancerRuleByID(rs.Primary.Attributes["gslb_rule_id"])
+		if err != nil {
+			// The GSLB rule is destroyed as well
+			continue
+		}
+
+		for _, lb := range r.Loadbalancerrule {
+			if lb.Id == rs.Primary.ID {
+				return fmt.Errorf("GSLB member %s still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackGSLBMember_basic = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  source_nat_ip = true
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_ipaddress" "foo" {
+  network_id = cloudstack_network.foo.id
+}
+
+resource "cloudstack_instance" "foobar1" {
+  name = "terraform-server1"
+  display_name = "terraform"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = cloudstack_network.foo.zone
+  expunge = true
+}
+
+resource "cloudstack_loadbalancer_rule" "foo" {
+  name = "terraform-lb"
+  ip_address_id = cloudstack_ipaddress.foo.id
+  algorithm = "roundrobin"
+  public_port = 80
+  private_port = 80
+  member_ids = [cloudstack_instance.foobar1.id]
+}
+
+resource "cloudstack_gslb_rule" "foo" {
+  name = "terraform-gslb"
+  domain_name = "terraform"
+  service_type = "http"
+}
+
+resource "cloudstack_gslb_member" "foo" {
+  gslb_rule_id = cloudstack_gslb_rule.foo.id
+  loadbalancer_rule_id = cloudstack_loadbalancer_rule.foo.id
+}`
+
+const testAccCloudStackGSLBMember_update = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  source_nat_ip = true
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_ipaddress" "foo" {
+  network_id = cloudstack_network.foo.id
+}
+
+resource "cloudstack_instance" "foobar1" {
+  name = "terraform-server1"
+  display_name = "terraform"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = cloudstack_network.foo.zone
+  expunge = true
+}
+
+resource "cloudstack_loadbalancer_rule" "foo" {
+  name = "terraform-lb"
+  ip_address_id = cloudstack_ipaddress.foo.id
+  algorithm = "roundrobin"
+  public_port = 80
+  private_port = 80
+  member_ids = [cloudstack_instance.foobar1.id]
+}
+
+resource "cloudstack_gslb_rule" "foo" {
+  name = "terraform-gslb"
+  domain_name = "terraform"
+  service_type = "http"
+}
+
+resource "cloudstack_gslb_member" "foo" {
+  gslb_rule_id = cloudstack_gslb_rule.foo.id
+  loadbalancer_rule_id = cloudstack_loadbalancer_rule.foo.id
+  weight = 50
+}`
@@ -0,0 +1,214 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudStackGSLBRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackGSLBRuleCreate,
+		Read:   resourceCloudStackGSLBRuleRead,
+		Update: resourceCloudStackGSLBRuleUpdate,
+		Delete: resourceCloudStackGSLBRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"service_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tcp", "udp", "http"}, false),
+			},
+
+			"method": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "roundrobin",
+				ValidateFunc: validation.StringInSlice([]string{
+					"roundrobin", "leastconn", "proximity",
+				}, false),
+			},
+
+			"persistence": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"sourceip"}, false),
+			},
+
+			"region_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+				ForceNew: true,
+			},
+
+			"loadbalancer_rule_ids": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceCloudStackGSLBRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewCreateGlobalLoadBalancerRuleParams(
+		d.Get("domain_name").(string),
+		d.Get("service_type").(string),
+		name,
+		d.Get("region_id").(int),
+	)
+
+	if description, ok := d.GetOk("description"); ok {
+		p.SetDescription(description.(string))
+	} else {
+		p.SetDescription(name)
+	}
+
+	p.SetGslblbmethod(d.Get("method").(string))
+
+	if persistence, ok := d.GetOk("persistence"); ok {
+		p.SetGslbstickysessionmethodname(persistence.(string))
+	}
+
+	r, err := cs.LoadBalancer.CreateGlobalLoadBalancerRule(p)
+	if err != nil {
+		return fmt.Errorf("Error creating GSLB rule %s: %s", name, err)
+	}
+
+	d.SetId(r.Id)
+
+	return resourceCloudStackGSLBRuleRead(d, meta)
+}
+
+func resourceCloudStackGSLBRuleRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the GSLB rule details
+	r, count, err := cs.LoadBalancer.GetGlobalLoadBalancerRuleByID(d.Id())
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] GSLB rule %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("name", r.Name)
+	d.Set("description", r.Description)
+	d.Set("domain_name", r.Gslbdomainname)
+	d.Set("service_type", r.Gslbservicetype)
+	d.Set("method", r.Gslblbmethod)
+	d.Set("persistence", r.Gslbstickysessionmethodname)
+	d.Set("region_id", r.Regionid)
+
+	var lbruleids []string
+	for _, lb := range r.Loadbalancerrule {
+		lbruleids = append(lbruleids, lb.Id)
+	}
+	d.Set("loadbalancer_rule_ids", lbruleids)
+
+	return nil
+}
+
+func resourceCloudStackGSLBRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if d.HasChange("description") || d.HasChange("method") || d.HasChange("persistence") {
+		// Create a new parameter struct
+		p := cs.LoadBalancer.NewUpdateGlobalLoadBalancerRuleParams(d.Id())
+
+		if d.HasChange("description") {
+			p.SetDescription(d.Get("description").(string))
+		}
+
+		if d.HasChange("method") {
+			p.SetGslblbmethod(d.Get("method").(string))
+		}
+
+		if d.HasChange("persistence") {
+			p.SetGslbstickysessionmethodname(d.Get("persistence").(string))
+		}
+
+		_, err := cs.LoadBalancer.UpdateGlobalLoadBalancerRule(p)
+		if err != nil {
+			return fmt.Errorf("Error updating GSLB rule %s: %s", d.Get("name").(string), err)
+		}
+	}
+
+	return resourceCloudStackGSLBRuleRead(d, meta)
+}
+
+func resourceCloudStackGSLBRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewDeleteGlobalLoadBalancerRuleParams(d.Id())
+
+	if _, err := cs.LoadBalancer.DeleteGlobalLoadBalancerRule(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting GSLB rule %s: %s", d.Get("name").(string), err)
+	}
+
+	return nil
+}
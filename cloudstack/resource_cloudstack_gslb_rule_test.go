@@ -0,0 +1,141 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackGSLBRule_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackGSLBRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackGSLBRule_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackGSLBRuleExists("cloudstack_gslb_rule.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_gslb_rule.foo", "domain_name", "terraform"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_gslb_rule.foo", "method", "roundrobin"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackGSLBRule_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackGSLBRuleExists("cloudstack_gslb_rule.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_gslb_rule.foo", "description", "terraform-gslb-updated"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_gslb_rule.foo", "method", "leastconn"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackGSLBRule_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackGSLBRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackGSLBRule_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_gslb_rule.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackGSLBRuleExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No GSLB rule ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		r, _, err := cs.LoadBalancer.GetGlobalLoadBalancerRuleByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if r.Id != rs.Primary.ID {
+			return fmt.Errorf("GSLB rule not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackGSLBRuleDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_gslb_rule" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No GSLB rule ID is set")
+		}
+
+		_, _, err := cs.LoadBalancer.GetGlobalLoadBalancerRuleByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("GSLB rule %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackGSLBRule_basic = `
+resource "cloudstack_gslb_rule" "foo" {
+  name = "terraform-gslb"
+  domain_name = "terraform"
+  service_type = "http"
+}`
+
+const testAccCloudStackGSLBRule_update = `
+resource "cloudstack_gslb_rule" "foo" {
+  name = "terraform-gslb"
+  description = "terraform-gslb-updated"
+  domain_name = "terraform"
+  service_type = "http"
+  method = "leastconn"
+}`
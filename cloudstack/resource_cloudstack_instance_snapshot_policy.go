@@ -0,0 +1,414 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudStackInstanceSnapshotPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackInstanceSnapshotPolicyCreate,
+		Read:   resourceCloudStackInstanceSnapshotPolicyRead,
+		Update: resourceCloudStackInstanceSnapshotPolicyUpdate,
+		Delete: resourceCloudStackInstanceSnapshotPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"virtual_machine_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"policy": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"interval_type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"HOURLY", "DAILY", "WEEKLY", "MONTHLY"}, false),
+						},
+
+						"max_snaps": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+
+						"schedule": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"timezone": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"zone_ids": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+					},
+				},
+			},
+
+			"volume_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceCloudStackInstanceSnapshotPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := verifyInstanceSnapshotPolicyParams(d); err != nil {
+		return err
+	}
+
+	// We need to set this upfront in order to be able to save a partial state
+	d.SetId(d.Get("virtual_machine_id").(string))
+
+	if err := reconcileInstanceSnapshotPolicies(d, meta); err != nil {
+		return err
+	}
+
+	return resourceCloudStackInstanceSnapshotPolicyRead(d, meta)
+}
+
+func resourceCloudStackInstanceSnapshotPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Make sure the virtual machine still exists
+	_, count, err := cs.VirtualMachine.GetVirtualMachineByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Virtual machine %s does no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	volumes, err := listInstanceVolumes(cs, d)
+	if err != nil {
+		return err
+	}
+
+	d.Set("virtual_machine_id", d.Id())
+
+	volumeIDs := make([]interface{}, 0, len(volumes))
+	policies := make(map[string]map[string]*cloudstack.SnapshotPolicy, len(volumes))
+	for _, v := range volumes {
+		volumeIDs = append(volumeIDs, v.Id)
+
+		policies[v.Id], err = listVolumeSnapshotPolicies(cs, v.Id)
+		if err != nil {
+			return err
+		}
+
+		setValueOrID(d, "project", v.Project, v.Projectid)
+	}
+	d.Set("volume_ids", volumeIDs)
+
+	// A policy is only reported when every volume of the virtual machine has
+	// an identical policy for that interval type. This way volumes which are
+	// added to the virtual machine later on, will show up as a change.
+	policy := &schema.Set{F: d.Get("policy").(*schema.Set).F}
+	if len(volumes) > 0 {
+		for intervalType, sp := range policies[volumes[0].Id] {
+			complete := true
+			for _, v := range volumes[1:] {
+				other, ok := policies[v.Id][intervalType]
+				if !ok || !equalSnapshotPolicies(sp, other) {
+					complete = false
+					break
+				}
+			}
+
+			if complete {
+				policy.Add(map[string]interface{}{
+					"interval_type": intervalType,
+					"max_snaps":     sp.Maxsnaps,
+					"schedule":      sp.Schedule,
+					"timezone":      sp.Timezone,
+					"zone_ids":      schema.NewSet(schema.HashString, stringSliceToInterface(snapshotPolicyZoneIDs(sp))),
+				})
+			}
+		}
+	}
+	d.Set("policy", policy)
+
+	return nil
+}
+
+func resourceCloudStackInstanceSnapshotPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if err := verifyInstanceSnapshotPolicyParams(d); err != nil {
+		return err
+	}
+
+	if d.HasChange("policy") {
+		// Delete the policies of interval types which are no longer configured
+		o, n := d.GetChange("policy")
+		removed := make(map[string]bool)
+		for _, p := range o.(*schema.Set).List() {
+			removed[p.(map[string]interface{})["interval_type"].(string)] = true
+		}
+		for _, p := range n.(*schema.Set).List() {
+			delete(removed, p.(map[string]interface{})["interval_type"].(string))
+		}
+
+		if err := deleteInstanceSnapshotPolicies(cs, d, removed); err != nil {
+			return err
+		}
+	}
+
+	if err := reconcileInstanceSnapshotPolicies(d, meta); err != nil {
+		return err
+	}
+
+	return resourceCloudStackInstanceSnapshotPolicyRead(d, meta)
+}
+
+func resourceCloudStackInstanceSnapshotPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	intervalTypes := make(map[string]bool)
+	for _, p := range d.Get("policy").(*schema.Set).List() {
+		intervalTypes[p.(map[string]interface{})["interval_type"].(string)] = true
+	}
+
+	return deleteInstanceSnapshotPolicies(cs, d, intervalTypes)
+}
+
+// reconcileInstanceSnapshotPolicies makes sure every volume of the virtual
+// machine has a snapshot policy matching each of the configured policies.
+func reconcileInstanceSnapshotPolicies(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	volumes, err := listInstanceVolumes(cs, d)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range volumes {
+		existing, err := listVolumeSnapshotPolicies(cs, v.Id)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range d.Get("policy").(*schema.Set).List() {
+			policy := p.(map[string]interface{})
+			intervalType := policy["interval_type"].(string)
+
+			var zoneIDs []string
+			for _, id := range policy["zone_ids"].(*schema.Set).List() {
+				zoneIDs = append(zoneIDs, id.(string))
+			}
+
+			if sp, ok := existing[intervalType]; ok &&
+				sp.Maxsnaps == policy["max_snaps"].(int) &&
+				sp.Schedule == policy["schedule"].(string) &&
+				sp.Timezone == policy["timezone"].(string) &&
+				equalStringSets(snapshotPolicyZoneIDs(sp), zoneIDs) {
+				continue
+			}
+
+			// Creating a policy for an interval type that already has a policy,
+			// will update the existing policy instead
+			sp := cs.Snapshot.NewCreateSnapshotPolicyParams(
+				intervalType,
+				policy["max_snaps"].(int),
+				policy["schedule"].(string),
+				policy["timezone"].(string),
+				v.Id,
+			)
+
+			if len(zoneIDs) > 0 {
+				sp.SetZoneids(zoneIDs)
+			}
+
+			log.Printf("[DEBUG] Creating %s snapshot policy for volume %s", intervalType, v.Id)
+			if _, err := cs.Snapshot.CreateSnapshotPolicy(sp); err != nil {
+				return fmt.Errorf(
+					"Error creating %s snapshot policy for volume %s: %s", intervalType, v.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteInstanceSnapshotPolicies deletes the snapshot policies with one of
+// the given interval types from all volumes of the virtual machine.
+func deleteInstanceSnapshotPolicies(
+	cs *cloudstack.CloudStackClient, d *schema.ResourceData, intervalTypes map[string]bool) error {
+	if len(intervalTypes) == 0 {
+		return nil
+	}
+
+	volumes, err := listInstanceVolumes(cs, d)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range volumes {
+		existing, err := listVolumeSnapshotPolicies(cs, v.Id)
+		if err != nil {
+			return err
+		}
+
+		for intervalType, sp := range existing {
+			if !intervalTypes[intervalType] {
+				continue
+			}
+
+			p := cs.Snapshot.NewDeleteSnapshotPoliciesParams()
+			p.SetId(sp.Id)
+
+			log.Printf("[DEBUG] Deleting %s snapshot policy %s of volume %s", intervalType, sp.Id, v.Id)
+			if _, err := cs.Snapshot.DeleteSnapshotPolicies(p); err != nil {
+				return fmt.Errorf("Error deleting snapshot policy %s: %s", sp.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func listInstanceVolumes(cs *cloudstack.CloudStackClient, d *schema.ResourceData) ([]*cloudstack.Volume, error) {
+	p := cs.Volume.NewListVolumesParams()
+	p.SetVirtualmachineid(d.Id())
+	p.SetListall(true)
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return nil, err
+	}
+
+	l, err := cs.Volume.ListVolumes(p)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing volumes of virtual machine %s: %s", d.Id(), err)
+	}
+
+	// Sort the volumes to get a stable order
+	sort.Slice(l.Volumes, func(i, j int) bool {
+		return l.Volumes[i].Id < l.Volumes[j].Id
+	})
+
+	return l.Volumes, nil
+}
+
+// listVolumeSnapshotPolicies returns the snapshot policies of a volume keyed
+// by their interval type, as a volume can only have one policy per type.
+func listVolumeSnapshotPolicies(
+	cs *cloudstack.CloudStackClient, volumeid string) (map[string]*cloudstack.SnapshotPolicy, error) {
+	p := cs.Snapshot.NewListSnapshotPoliciesParams()
+	p.SetVolumeid(volumeid)
+
+	l, err := cs.Snapshot.ListSnapshotPolicies(p)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing snapshot policies of volume %s: %s", volumeid, err)
+	}
+
+	policies := make(map[string]*cloudstack.SnapshotPolicy, l.Count)
+	for _, sp := range l.SnapshotPolicies {
+		policies[intervalTypeToString(sp.Intervaltype)] = sp
+	}
+
+	return policies, nil
+}
+
+func equalSnapshotPolicies(a, b *cloudstack.SnapshotPolicy) bool {
+	return a.Maxsnaps == b.Maxsnaps &&
+		a.Schedule == b.Schedule &&
+		a.Timezone == b.Timezone &&
+		equalStringSets(snapshotPolicyZoneIDs(a), snapshotPolicyZoneIDs(b))
+}
+
+func equalStringSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sa := append([]string(nil), a...)
+	sb := append([]string(nil), b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func stringSliceToInterface(s []string) []interface{} {
+	r := make([]interface{}, len(s))
+	for i, v := range s {
+		r[i] = v
+	}
+	return r
+}
+
+func verifyInstanceSnapshotPolicyParams(d *schema.ResourceData) error {
+	seen := make(map[string]bool)
+	for _, p := range d.Get("policy").(*schema.Set).List() {
+		intervalType := p.(map[string]interface{})["interval_type"].(string)
+		if seen[intervalType] {
+			return fmt.Errorf(
+				"Only one policy per interval type is allowed, found multiple %s policies",
+				strings.ToLower(intervalType))
+		}
+		seen[intervalType] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackInstanceSnapshotPolicy_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceSnapshotPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstanceSnapshotPolicy_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceSnapshotPolicyExists(
+						"cloudstack_instance_snapshot_policy.foo", 2),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_snapshot_policy.foo", "policy.#", "2"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_snapshot_policy.foo", "volume_ids.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstanceSnapshotPolicy_update(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceSnapshotPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstanceSnapshotPolicy_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceSnapshotPolicyExists(
+						"cloudstack_instance_snapshot_policy.foo", 2),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstanceSnapshotPolicy_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceSnapshotPolicyExists(
+						"cloudstack_instance_snapshot_policy.foo", 1),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_snapshot_policy.foo", "policy.#", "1"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance_snapshot_policy.foo", "volume_ids.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstanceSnapshotPolicy_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceSnapshotPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstanceSnapshotPolicy_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_instance_snapshot_policy.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackInstanceSnapshotPolicyExists(n string, policies int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No instance snapshot policy ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+		p := cs.Volume.NewListVolumesParams()
+		p.SetVirtualmachineid(rs.Primary.ID)
+		l, err := cs.Volume.ListVolumes(p)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range l.Volumes {
+			sp := cs.Snapshot.NewListSnapshotPoliciesParams()
+			sp.SetVolumeid(v.Id)
+			r, err := cs.Snapshot.ListSnapshotPolicies(sp)
+			if err != nil {
+				return err
+			}
+
+			if r.Count != policies {
+				return fmt.Errorf(
+					"Volume %s has %d snapshot policies, expected %d", v.Id, r.Count, policies)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackInstanceSnapshotPolicyDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_instance_snapshot_policy" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No instance snapshot policy ID is set")
+		}
+
+		p := cs.Volume.NewListVolumesParams()
+		p.SetVirtualmachineid(rs.Primary.ID)
+		l, err := cs.Volume.ListVolumes(p)
+		if err != nil {
+			continue
+		}
+
+		for _, v := range l.Volumes {
+			sp := cs.Snapshot.NewListSnapshotPoliciesParams()
+			sp.SetVolumeid(v.Id)
+			r, err := cs.Snapshot.ListSnapshotPolicies(sp)
+			if err == nil && r.Count > 0 {
+				return fmt.Errorf("Volume %s still has snapshot policies", v.Id)
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackInstanceSnapshotPolicy_basic = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name   = "name"
+    value  = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = data.cloudstack_zone.zone.name
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform"
+  service_offering = "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = data.cloudstack_zone.zone.name
+  expunge = true
+}
+
+resource "cloudstack_disk" "foo" {
+  name               = "terraform-disk"
+  attach             = true
+  disk_offering      = "Small"
+  virtual_machine_id = cloudstack_instance.foobar.id
+  zone               = data.cloudstack_zone.zone.name
+}
+
+resource "cloudstack_instance_snapshot_policy" "foo" {
+  virtual_machine_id = cloudstack_disk.foo.virtual_machine_id
+
+  policy {
+    interval_type = "HOURLY"
+    max_snaps     = 6
+    schedule      = "0"
+    timezone      = "UTC"
+  }
+
+  policy {
+    interval_type = "DAILY"
+    max_snaps     = 7
+    schedule      = "30:02"
+    timezone      = "UTC"
+    zone_ids      = [data.cloudstack_zone.zone.id]
+  }
+}
+`
+
+const testAccCloudStackInstanceSnapshotPolicy_update = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name   = "name"
+    value  = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = data.cloudstack_zone.zone.name
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform"
+  service_offering = "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = data.cloudstack_zone.zone.name
+  expunge = true
+}
+
+resource "cloudstack_disk" "foo" {
+  name               = "terraform-disk"
+  attach             = true
+  disk_offering      = "Small"
+  virtual_machine_id = cloudstack_instance.foobar.id
+  zone               = data.cloudstack_zone.zone.name
+}
+
+resource "cloudstack_disk" "bar" {
+  name               = "terraform-disk-2"
+  attach             = true
+  disk_offering      = "Small"
+  virtual_machine_id = cloudstack_instance.foobar.id
+  zone               = data.cloudstack_zone.zone.name
+}
+
+resource "cloudstack_instance_snapshot_policy" "foo" {
+  virtual_machine_id = cloudstack_disk.bar.virtual_machine_id
+
+  policy {
+    interval_type = "DAILY"
+    max_snaps     = 14
+    schedule      = "30:02"
+    timezone      = "UTC"
+    zone_ids      = [data.cloudstack_zone.zone.id]
+  }
+}
+`
@@ -20,6 +20,7 @@
 package cloudstack
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -32,11 +33,14 @@ func resourceCloudStackIPAddress() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceCloudStackIPAddressCreate,
 		Read:   resourceCloudStackIPAddressRead,
+		Update: resourceCloudStackIPAddressUpdate,
 		Delete: resourceCloudStackIPAddressDelete,
 		Importer: &schema.ResourceImporter{
 			State: resourceCloudStackIPAddressImport,
 		},
 
+		CustomizeDiff: resourceCloudStackIPAddressCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"is_portable": {
 				Type:     schema.TypeBool,
@@ -45,16 +49,21 @@ func resourceCloudStackIPAddress() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"reserve_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
 			"network_id": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 
 			"vpc_id": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 
 			"zone": {
@@ -77,6 +86,12 @@ func resourceCloudStackIPAddress() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"for_display": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
 			"is_source_nat": {
 				Type:     schema.TypeBool,
 				Computed: true,
@@ -94,8 +109,27 @@ func resourceCloudStackIPAddressCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	if d.Get("reserve_only").(bool) {
+		if err := reserveIPAddress(d, meta); err != nil {
+			return err
+		}
+
+		// A reserved IP only needs to be associated when it is used by a
+		// network or VPC right away
+		_, network := d.GetOk("network_id")
+		_, vpc := d.GetOk("vpc_id")
+		if !network && !vpc {
+			if err := setTags(cs, d, "PublicIpAddress"); err != nil {
+				return fmt.Errorf("Error setting tags on the IP address: %s", err)
+			}
+
+			return resourceCloudStackIPAddressRead(d, meta)
+		}
+	}
+
 	// Create a new parameter struct
 	p := cs.Address.NewAssociateIpAddressParams()
+	p.SetFordisplay(d.Get("for_display").(bool))
 
 	if d.Get("is_portable").(bool) {
 		p.SetIsportable(true)
@@ -104,9 +138,6 @@ func resourceCloudStackIPAddressCreate(d *schema.ResourceData, meta interface{})
 	if networkid, ok := d.GetOk("network_id"); ok {
 		// Set the networkid
 		p.SetNetworkid(networkid.(string))
-		if vpcid, ok := d.GetOk("vpc_id"); ok && vpcid.(string) != "" {
-			return fmt.Errorf("set only network_id or vpc_id")
-		}
 
 		// If no project is explicitly set, try to inherit it from the network
 		if _, ok := d.GetOk("project"); !ok {
@@ -160,6 +191,13 @@ func resourceCloudStackIPAddressCreate(d *schema.ResourceData, meta interface{})
 	// Associate a new IP address
 	r, err := cs.Address.AssociateIpAddress(p)
 	if err != nil {
+		if d.Id() != "" {
+			// Don't leave the reserved IP behind without a resource to manage it
+			if e := releaseIPAddress(cs, d.Id()); e != nil {
+				log.Printf("[WARN] Error releasing reserved IP address %s: %s", d.Id(), e)
+			}
+			d.SetId("")
+		}
 		return fmt.Errorf("Error associating a new IP address: %s", err)
 	}
 
@@ -174,6 +212,53 @@ func resourceCloudStackIPAddressCreate(d *schema.ResourceData, meta interface{})
 	return resourceCloudStackIPAddressRead(d, meta)
 }
 
+// reserveIPAddress looks up the configured address in the public IP pool of
+// the zone and reserves it for the account or project, so it stays with the
+// account when it is disassociated from a network.
+func reserveIPAddress(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Retrieve the zone ID
+	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+	if e != nil {
+		return e.Error()
+	}
+
+	ipaddress := d.Get("ip_address").(string)
+
+	lp := cs.Address.NewListPublicIpAddressesParams()
+	lp.SetIpaddress(ipaddress)
+	lp.SetZoneid(zoneid)
+	lp.SetAllocatedonly(false)
+	lp.SetForvirtualnetwork(true)
+
+	l, err := cs.Address.ListPublicIpAddresses(lp)
+	if err != nil {
+		return fmt.Errorf("Error retrieving IP address %s: %s", ipaddress, err)
+	}
+	if l.Count != 1 {
+		return fmt.Errorf("Unable to find IP address %s in zone %s", ipaddress, d.Get("zone").(string))
+	}
+
+	// Create a new parameter struct
+	p := cs.Address.NewReserveIpAddressParams(l.PublicIpAddresses[0].Id)
+	p.SetFordisplay(d.Get("for_display").(bool))
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	r, err := cs.Address.ReserveIpAddress(p)
+	if err != nil {
+		return fmt.Errorf("Error reserving IP address %s: %s", ipaddress, err)
+	}
+
+	d.SetId(r.Id)
+
+	return nil
+}
+
 func resourceCloudStackIPAddressImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	cs := meta.(*cloudstack.CloudStackClient)
 
@@ -242,6 +327,7 @@ func resourceCloudStackIPAddressRead(d *schema.ResourceData, meta interface{}) e
 
 	d.Set("is_portable", ip.Isportable)
 	d.Set("is_source_nat", ip.Issourcenat)
+	d.Set("for_display", ip.Fordisplay)
 
 	// Updated the IP address
 	d.Set("ip_address", ip.Ipaddress)
@@ -275,10 +361,126 @@ func resourceCloudStackIPAddressRead(d *schema.ResourceData, meta interface{}) e
 	return nil
 }
 
+func resourceCloudStackIPAddressUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Move a reserved or portable IP address to another network or VPC
+	if d.HasChanges("network_id", "vpc_id") {
+		if err := verifyIPAddressParams(d); err != nil {
+			return err
+		}
+
+		if err := moveIPAddress(d, meta); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("for_display") {
+		p := cs.Address.NewUpdateIpAddressParams(d.Id())
+		p.SetFordisplay(d.Get("for_display").(bool))
+
+		if _, err := cs.Address.UpdateIpAddress(p); err != nil {
+			return fmt.Errorf(
+				"Error updating the display setting of IP address %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("tags") {
+		if err := updateTags(cs, d, "PublicIpAddress"); err != nil {
+			return fmt.Errorf("Error updating tags on IP address %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceCloudStackIPAddressRead(d, meta)
+}
+
+// moveIPAddress disassociates the IP address from its current network or VPC
+// and associates the same address with the new one. This is only allowed for
+// reserved and portable IP addresses. A reserved IP address stays with the
+// account when it is disassociated, while a portable IP address is not bound
+// to the zone of its network and is associated again right away.
+func moveIPAddress(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if err := verifyIPAddressMove(d.Get("reserve_only").(bool), d.Get("is_portable").(bool),
+		d.Get("network_id").(string) != "", d.Get("vpc_id").(string) != ""); err != nil {
+		return fmt.Errorf("Unable to move IP address %s: %s", d.Get("ip_address").(string), err)
+	}
+
+	oldNetwork, networkid := d.GetChange("network_id")
+	oldVPC, vpcid := d.GetChange("vpc_id")
+
+	if oldNetwork.(string) != "" || oldVPC.(string) != "" {
+		p := cs.Address.NewDisassociateIpAddressParams(d.Id())
+		if _, err := cs.Address.DisassociateIpAddress(p); err != nil {
+			return fmt.Errorf("Error disassociating IP address %s: %s", d.Id(), err)
+		}
+
+		// A reserved IP address is now only reserved, so make sure the state
+		// says so if associating it with the new network or VPC fails
+		d.Set("network_id", "")
+		d.Set("vpc_id", "")
+	}
+
+	// A reserved IP address can stay with the account without a network
+	if networkid.(string) == "" && vpcid.(string) == "" {
+		return nil
+	}
+
+	p := cs.Address.NewAssociateIpAddressParams()
+	p.SetIpaddress(d.Get("ip_address").(string))
+	p.SetFordisplay(d.Get("for_display").(bool))
+
+	if d.Get("is_portable").(bool) {
+		p.SetIsportable(true)
+	}
+
+	if networkid.(string) != "" {
+		p.SetNetworkid(networkid.(string))
+	}
+
+	if vpcid.(string) != "" {
+		p.SetVpcid(vpcid.(string))
+	}
+
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	r, err := cs.Address.AssociateIpAddress(p)
+	if err != nil {
+		// Make sure the ID still points at the reserved IP address, or
+		// remove it from the state if the address is no longer allocated,
+		// which is the case for a portable IP address
+		if _, count, e := cs.Address.GetPublicIpAddressByID(
+			d.Id(), cloudstack.WithProject(d.Get("project").(string))); e != nil && count == 0 {
+			d.SetId("")
+		}
+
+		return fmt.Errorf(
+			"Error associating IP address %s: %s", d.Get("ip_address").(string), err)
+	}
+
+	d.SetId(r.Id)
+	d.Set("network_id", networkid)
+	d.Set("vpc_id", vpcid)
+
+	return nil
+}
+
 func resourceCloudStackIPAddressDelete(d *schema.ResourceData, meta interface{}) error {
-	if !d.Get("is_source_nat").(bool) {
-		cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*cloudstack.CloudStackClient)
 
+	if d.Get("is_source_nat").(bool) {
+		return nil
+	}
+
+	_, network := d.GetOk("network_id")
+	_, vpc := d.GetOk("vpc_id")
+
+	// A reserved IP address that isn't used by a network or VPC only needs
+	// to be released
+	if !d.Get("reserve_only").(bool) || network || vpc {
 		// Create a new parameter struct
 		p := cs.Address.NewDisassociateIpAddressParams(d.Id())
 
@@ -295,6 +497,86 @@ func resourceCloudStackIPAddressDelete(d *schema.ResourceData, meta interface{})
 		}
 	}
 
+	if d.Get("reserve_only").(bool) {
+		if err := releaseIPAddress(cs, d.Id()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func releaseIPAddress(cs *cloudstack.CloudStackClient, id string) error {
+	p := cs.Address.NewReleaseIpAddressParams(id)
+
+	if _, err := cs.Address.ReleaseIpAddress(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", id)) {
+			return nil
+		}
+
+		return fmt.Errorf("Error releasing IP address %s: %s", id, err)
+	}
+
+	return nil
+}
+
+func resourceCloudStackIPAddressCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChanges("network_id", "vpc_id") {
+		return nil
+	}
+
+	// Reserved and portable IP addresses are moved to the new network or VPC
+	// in place, any other IP address needs to be replaced
+	reserved := d.Get("reserve_only").(bool)
+	portable := d.Get("is_portable").(bool)
+	if !reserved && !portable {
+		for _, k := range []string{"network_id", "vpc_id"} {
+			if d.HasChange(k) {
+				if err := d.ForceNew(k); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	// An unknown ID will have a value once it is known
+	network := d.Get("network_id").(string) != "" || !d.NewValueKnown("network_id")
+	vpc := d.Get("vpc_id").(string) != "" || !d.NewValueKnown("vpc_id")
+
+	// Reject a move that can't be done, instead of replacing the IP address
+	if err := verifyIPAddressMove(reserved, portable, network, vpc); err != nil {
+		return fmt.Errorf("Unable to move IP address %s: %s", d.Get("ip_address").(string), err)
+	}
+
+	return nil
+}
+
+// verifyIPAddressMove returns an error if a reserved or portable IP address
+// can't be moved to the given network or VPC.
+func verifyIPAddressMove(reserved, portable, network, vpc bool) error {
+	if network && vpc {
+		return fmt.Errorf("set only network_id or vpc_id")
+	}
+
+	// A reserved IP address can stay with the account without a network
+	if reserved {
+		return nil
+	}
+
+	if !portable {
+		return fmt.Errorf("only reserved and portable IP addresses can be moved to another network or VPC")
+	}
+
+	if !network && !vpc {
+		return fmt.Errorf("a portable IP address can only be moved to another network or VPC, " +
+			"set either network_id or vpc_id")
+	}
+
 	return nil
 }
 
@@ -303,6 +585,16 @@ func verifyIPAddressParams(d *schema.ResourceData) error {
 	_, network := d.GetOk("network_id")
 	_, vpc := d.GetOk("vpc_id")
 	_, zone := d.GetOk("zone")
+	_, ipaddress := d.GetOk("ip_address")
+
+	if network && vpc {
+		return fmt.Errorf("set only network_id or vpc_id")
+	}
+
+	if d.Get("reserve_only").(bool) && (!zone || !ipaddress) {
+		return fmt.Errorf(
+			"You must supply a value for both the 'zone' and 'ip_address' parameters for a reserved IP")
+	}
 
 	if portable && ((network && vpc) || (!network && !vpc)) {
 		return fmt.Errorf(
@@ -108,6 +108,45 @@ func TestAccCloudStackIPAddress_specificIP(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackIPAddress_reserveOnly(t *testing.T) {
+	var ipaddr cloudstack.PublicIpAddress
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackIPAddressDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackIPAddress_reserveOnly,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackIPAddressExists(
+						"cloudstack_ipaddress.foo", &ipaddr),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipaddress.foo", "ip_address", "10.2.2.11"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipaddress.foo", "reserve_only", "true"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipaddress.foo", "for_display", "true"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackIPAddress_reserveOnlyNetwork,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackIPAddressExists(
+						"cloudstack_ipaddress.foo", &ipaddr),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipaddress.foo", "ip_address", "10.2.2.11"),
+					resource.TestCheckResourceAttrPair(
+						"cloudstack_ipaddress.foo", "network_id", "cloudstack_network.foo", "id"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipaddress.foo", "for_display", "false"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackIPAddress_vpcid_with_network_id(t *testing.T) {
 
 	regex := regexp.MustCompile("set only network_id or vpc_id")
@@ -302,6 +341,90 @@ resource "cloudstack_ipaddress" "foo" {
   ip_address = cloudstack_vlan_ip_range.foo.end_ip
 }`
 
+const testAccCloudStackIPAddress_reserveOnly = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+data "cloudstack_physical_network" "pn" {
+  filter {
+    name  = "zone_name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_vlan_ip_range" "foo" {
+  physical_network_id = data.cloudstack_physical_network.pn.id
+  zone_id              = data.cloudstack_zone.zone.id
+  for_virtual_network  = true
+  vlan                 = "vlan://456"
+  gateway              = "10.2.2.1"
+  netmask              = "255.255.255.0"
+  start_ip             = "10.2.2.10"
+  end_ip               = "10.2.2.11"
+}
+
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  source_nat_ip = true
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_ipaddress" "foo" {
+  zone = data.cloudstack_zone.zone.name
+  ip_address = cloudstack_vlan_ip_range.foo.end_ip
+  reserve_only = true
+}`
+
+const testAccCloudStackIPAddress_reserveOnlyNetwork = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+data "cloudstack_physical_network" "pn" {
+  filter {
+    name  = "zone_name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_vlan_ip_range" "foo" {
+  physical_network_id = data.cloudstack_physical_network.pn.id
+  zone_id              = data.cloudstack_zone.zone.id
+  for_virtual_network  = true
+  vlan                 = "vlan://456"
+  gateway              = "10.2.2.1"
+  netmask              = "255.255.255.0"
+  start_ip             = "10.2.2.10"
+  end_ip               = "10.2.2.11"
+}
+
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  source_nat_ip = true
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_ipaddress" "foo" {
+  zone = data.cloudstack_zone.zone.name
+  network_id = cloudstack_network.foo.id
+  ip_address = cloudstack_vlan_ip_range.foo.end_ip
+  reserve_only = true
+  for_display = false
+}`
+
 const testAccCloudStackIPAddress_vpcid_with_network_id = `
 resource "cloudstack_vpc" "foo" {
   name = "terraform-vpc"
@@ -0,0 +1,205 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackIPv4Subnet() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackIPv4SubnetCreate,
+		Read:   resourceCloudStackIPv4SubnetRead,
+		Update: resourceCloudStackIPv4SubnetUpdate,
+		Delete: resourceCloudStackIPv4SubnetDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"subnet": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"zone_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				RequiredWith:  []string{"domain_id"},
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackIPv4SubnetCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	subnet := d.Get("subnet").(string)
+
+	// Create a new parameter struct
+	p := cs.Network.NewCreateIpv4SubnetForZoneParams(subnet, d.Get("zone_id").(string))
+
+	// Dedicate the subnet right away if requested
+	if account, ok := d.GetOk("account"); ok {
+		p.SetAccount(account.(string))
+	}
+
+	if domainid, ok := d.GetOk("domain_id"); ok {
+		p.SetDomainid(domainid.(string))
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	r, err := cs.Network.CreateIpv4SubnetForZone(p)
+	if err != nil {
+		return fmt.Errorf("Error creating IPv4 subnet %s: %s", subnet, err)
+	}
+
+	d.SetId(r.Id)
+
+	return resourceCloudStackIPv4SubnetRead(d, meta)
+}
+
+func resourceCloudStackIPv4SubnetRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the IPv4 subnet details
+	s, count, err := cs.Network.GetIpv4SubnetForZoneByID(d.Id())
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] IPv4 subnet %s does no longer exist", d.Get("subnet").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("subnet", s.Subnet)
+	d.Set("zone_id", s.Zoneid)
+
+	// A subnet is either dedicated to a project or to an account
+	if s.Projectid != "" {
+		setValueOrID(d, "project", s.Project, s.Projectid)
+		d.Set("account", "")
+		d.Set("domain_id", "")
+	} else {
+		d.Set("project", "")
+		d.Set("account", s.Account)
+		d.Set("domain_id", s.Domainid)
+	}
+
+	return nil
+}
+
+func resourceCloudStackIPv4SubnetUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if d.HasChange("subnet") {
+		// Create a new parameter struct
+		p := cs.Network.NewUpdateIpv4SubnetForZoneParams(d.Id(), d.Get("subnet").(string))
+
+		_, err := cs.Network.UpdateIpv4SubnetForZone(p)
+		if err != nil {
+			return fmt.Errorf("Error updating IPv4 subnet %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("account") || d.HasChange("domain_id") || d.HasChange("project") {
+		o, _ := d.GetChange("account")
+		op, _ := d.GetChange("project")
+
+		// Release the subnet first if it was dedicated before
+		if o.(string) != "" || op.(string) != "" {
+			p := cs.Network.NewReleaseIpv4SubnetForZoneParams(d.Id())
+			if _, err := cs.Network.ReleaseIpv4SubnetForZone(p); err != nil {
+				return fmt.Errorf("Error releasing IPv4 subnet %s: %s", d.Id(), err)
+			}
+		}
+
+		_, account := d.GetOk("account")
+		_, project := d.GetOk("project")
+
+		if account || project {
+			p := cs.Network.NewDedicateIpv4SubnetForZoneParams(d.Id())
+
+			if account, ok := d.GetOk("account"); ok {
+				p.SetAccount(account.(string))
+				p.SetDomainid(d.Get("domain_id").(string))
+			}
+
+			// If there is a project supplied, we retrieve and set the project id
+			if err := setProjectid(p, cs, d); err != nil {
+				return err
+			}
+
+			if _, err := cs.Network.DedicateIpv4SubnetForZone(p); err != nil {
+				return fmt.Errorf("Error dedicating IPv4 subnet %s: %s", d.Id(), err)
+			}
+		}
+	}
+
+	return resourceCloudStackIPv4SubnetRead(d, meta)
+}
+
+func resourceCloudStackIPv4SubnetDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.Network.NewDeleteIpv4SubnetForZoneParams(d.Id())
+
+	if _, err := cs.Network.DeleteIpv4SubnetForZone(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting IPv4 subnet %s: %s", d.Get("subnet").(string), err)
+	}
+
+	return nil
+}
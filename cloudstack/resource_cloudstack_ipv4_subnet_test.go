@@ -0,0 +1,147 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackIPv4Subnet_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackIPv4SubnetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackIPv4Subnet_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackIPv4SubnetExists("cloudstack_ipv4_subnet.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipv4_subnet.foo", "subnet", "172.30.0.0/20"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackIPv4Subnet_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackIPv4SubnetExists("cloudstack_ipv4_subnet.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipv4_subnet.foo", "subnet", "172.30.0.0/19"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackIPv4Subnet_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackIPv4SubnetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackIPv4Subnet_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_ipv4_subnet.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackIPv4SubnetExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No IPv4 subnet ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		subnet, _, err := cs.Network.GetIpv4SubnetForZoneByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if subnet.Id != rs.Primary.ID {
+			return fmt.Errorf("IPv4 subnet not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackIPv4SubnetDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_ipv4_subnet" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No IPv4 subnet ID is set")
+		}
+
+		_, _, err := cs.Network.GetIpv4SubnetForZoneByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("IPv4 subnet %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackIPv4Subnet_basic = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_ipv4_subnet" "foo" {
+  subnet  = "172.30.0.0/20"
+  zone_id = data.cloudstack_zone.zone.id
+}`
+
+const testAccCloudStackIPv4Subnet_update = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_ipv4_subnet" "foo" {
+  subnet  = "172.30.0.0/19"
+  zone_id = data.cloudstack_zone.zone.id
+}`
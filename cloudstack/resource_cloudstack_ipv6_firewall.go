@@ -0,0 +1,590 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudStackIPv6Firewall() *schema.Resource {
+	r := &schema.Resource{
+		Create:      resourceCloudStackIPv6FirewallCreate,
+		ReadContext: readReportingUnmanagedRules(resourceCloudStackIPv6FirewallRead),
+		Update:      resourceCloudStackIPv6FirewallUpdate,
+		Delete:      resourceCloudStackIPv6FirewallDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackIPv6FirewallImport,
+		},
+		CustomizeDiff: firewallCustomizeDiff(ipv6FirewallPortRules),
+
+		Schema: map[string]*schema.Schema{
+			"network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"managed": managedSchema(),
+
+			"unmanaged_rules": unmanagedRulesSchema(),
+
+			"rule": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"traffic_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "ingress",
+							ValidateFunc: validation.StringInSlice([]string{"ingress", "egress"}, false),
+						},
+
+						"cidr_list": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+
+						"dest_cidr_list": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+
+						"protocol": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"icmp_type": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+						},
+
+						"icmp_code": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+						},
+
+						"ports": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+
+						"uuids": {
+							Type:     schema.TypeMap,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"parallelism": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  2,
+			},
+		},
+	}
+
+	// The managed field was a bool before it also accepted "report"
+	r.SchemaVersion = 1
+	r.StateUpgraders = []schema.StateUpgrader{managedStateUpgradeV0(r)}
+
+	return r
+}
+
+func resourceCloudStackIPv6FirewallCreate(d *schema.ResourceData, meta interface{}) error {
+	// Make sure all required parameters are there
+	if err := verifyFirewallParams(d); err != nil {
+		return err
+	}
+
+	// We need to set this upfront in order to be able to save a partial state
+	d.SetId(d.Get("network_id").(string))
+
+	// Create all rules that are configured
+	if nrs := d.Get("rule").(*schema.Set); nrs.Len() > 0 {
+		// Create an empty schema.Set to hold all rules
+		rules := resourceCloudStackIPv6Firewall().Schema["rule"].ZeroValue().(*schema.Set)
+
+		err := createIPv6FirewallRules(d, meta, rules, nrs)
+
+		// We need to update this first to preserve the correct state
+		d.Set("rule", rules)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackIPv6FirewallRead(d, meta)
+}
+
+func createIPv6FirewallRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, nrs *schema.Set) error {
+	return applyFirewallRules(d, rules, nrs, "creating", func(sem jobSlots, rule map[string]interface{}) error {
+		return createIPv6FirewallRule(d, meta, sem, rule)
+	})
+}
+
+func createIPv6FirewallRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Make sure all required rule parameters are there
+	if err := verifyIPv6FirewallRuleParams(d, portAliasesOf(cs), rule); err != nil {
+		return err
+	}
+
+	// Create a new parameter struct
+	p := cs.Firewall.NewCreateIpv6FirewallRuleParams(rule["protocol"].(string))
+	p.SetNetworkid(d.Id())
+	p.SetTraffictype(rule["traffic_type"].(string))
+
+	// Set the CIDR lists
+	if cidrList := setToStrings(rule["cidr_list"]); len(cidrList) > 0 {
+		p.SetCidrlist(cidrList)
+	}
+
+	if destCidrList := setToStrings(rule["dest_cidr_list"]); len(destCidrList) > 0 {
+		p.SetDestcidrlist(destCidrList)
+	}
+
+	// If the protocol is ICMP set the needed ICMP parameters
+	if strings.ToLower(rule["protocol"].(string)) == "icmp" {
+		p.SetIcmptype(rule["icmp_type"].(int))
+		p.SetIcmpcode(rule["icmp_code"].(int))
+	}
+
+	return createFirewallRuleIDs(portAliasesOf(cs), rule, func(startPort, endPort int) (string, error) {
+		if startPort != 0 {
+			p.SetStartport(startPort)
+			p.SetEndport(endPort)
+		}
+
+		sem.take()
+		r, err := jobs(cs).client().Firewall.CreateIpv6FirewallRule(p)
+		sem.release()
+		if err == nil {
+			err = jobs(cs).wait(r.JobID)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return r.Id, nil
+	})
+}
+
+func resourceCloudStackIPv6FirewallRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get all the rules from the running environment
+	p := cs.Firewall.NewListIpv6FirewallRulesParams()
+	p.SetNetworkid(d.Id())
+	p.SetListall(true)
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	l, err := cs.Firewall.ListIpv6FirewallRules(p)
+	if err != nil {
+		return err
+	}
+
+	// Make a map of all the rules so we can easily find a rule
+	ruleMap := make(map[string]*cloudstack.Ipv6FirewallRule, l.Count)
+	for _, r := range l.Ipv6FirewallRules {
+		ruleMap[r.Id] = r
+	}
+
+	// Create an empty schema.Set to hold all rules
+	rules := resourceCloudStackIPv6Firewall().Schema["rule"].ZeroValue().(*schema.Set)
+
+	// Read all rules that are configured
+	readFirewallRules(d.Get("rule").(*schema.Set), rules, func(rule map[string]interface{}, key, id string) bool {
+		// Get the rule
+		r, ok := ruleMap[id]
+		if !ok {
+			return false
+		}
+
+		// Delete the known rule so only unknown rules remain in the ruleMap
+		delete(ruleMap, id)
+
+		// Update the values
+		setIPv6FirewallRuleValues(rule, r)
+		if key == "icmp" {
+			rule["icmp_type"] = r.Icmptype
+			rule["icmp_code"] = r.Icmpcode
+		}
+
+		return true
+	})
+
+	unknown := make(map[string]map[string]interface{}, len(ruleMap))
+	for uuid, r := range ruleMap {
+		unknown[uuid] = ipv6FirewallUnmanagedRule(r)
+	}
+
+	return setFirewallRules(d, rules, unknown)
+}
+
+func setIPv6FirewallRuleValues(rule map[string]interface{}, r *cloudstack.Ipv6FirewallRule) {
+	rule["protocol"] = r.Protocol
+	rule["traffic_type"] = strings.ToLower(r.Traffictype)
+	rule["cidr_list"] = cidrSetFromList(r.Cidrlist)
+	rule["dest_cidr_list"] = cidrSetFromList(r.Destcidrlist)
+}
+
+func resourceCloudStackIPv6FirewallUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Make sure all required parameters are there
+	if err := verifyFirewallParams(d); err != nil {
+		return err
+	}
+
+	// Check if the rule set as a whole has changed
+	if d.HasChange("rule") {
+		o, n := d.GetChange("rule")
+		ors := o.(*schema.Set).Difference(n.(*schema.Set))
+		nrs := n.(*schema.Set).Difference(o.(*schema.Set))
+
+		// We need to start with a rule set containing all the rules we
+		// already have and want to keep. Any rules that are not deleted
+		// correctly and any newly created rules, will be added to this
+		// set to make sure we end up in a consistent state
+		rules := o.(*schema.Set).Intersection(n.(*schema.Set))
+
+		// Rules of which only the CIDR lists changed are updated in place
+		if ors.Len() > 0 && nrs.Len() > 0 {
+			err := updateIPv6FirewallRules(d, meta, rules, ors, nrs)
+
+			// We need to update this first to preserve the correct state
+			d.Set("rule", rules)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		// Then loop through all the remaining old rules and delete them
+		if ors.Len() > 0 {
+			err := deleteIPv6FirewallRules(d, meta, rules, ors)
+
+			// We need to update this first to preserve the correct state
+			d.Set("rule", rules)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		// Then loop through all the remaining new rules and create them
+		if nrs.Len() > 0 {
+			err := createIPv6FirewallRules(d, meta, rules, nrs)
+
+			// We need to update this first to preserve the correct state
+			d.Set("rule", rules)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceCloudStackIPv6FirewallRead(d, meta)
+}
+
+// updateIPv6FirewallRules matches old and new rules that only differ in their
+// CIDR lists and updates them in place. Matched rules are removed from ors and
+// nrs, so only the rules that need to be replaced are left over.
+func updateIPv6FirewallRules(
+	d *schema.ResourceData, meta interface{}, rules *schema.Set, ors *schema.Set, nrs *schema.Set) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Index the old rules by everything except their CIDR lists
+	candidates := make(map[string][]map[string]interface{})
+	for _, rule := range ors.List() {
+		rule := rule.(map[string]interface{})
+		key := ipv6FirewallRuleKey(rule)
+		candidates[key] = append(candidates[key], rule)
+	}
+
+	var errs *multierror.Error
+	for _, rule := range nrs.List() {
+		rule := rule.(map[string]interface{})
+		key := ipv6FirewallRuleKey(rule)
+		if len(candidates[key]) == 0 {
+			continue
+		}
+
+		old := candidates[key][0]
+		candidates[key] = candidates[key][1:]
+
+		// Move the existing UUIDs over to the new rule
+		uuids := make(map[string]interface{})
+		for k, id := range old["uuids"].(map[string]interface{}) {
+			if k == "%" {
+				continue
+			}
+			uuids[k] = id
+		}
+
+		ors.Remove(old)
+		nrs.Remove(rule)
+
+		var failed bool
+		for _, id := range uuids {
+			// Create a new parameter struct
+			p := cs.Firewall.NewUpdateIpv6FirewallRuleParams(id.(string))
+			p.SetCidrlist(setToStrings(rule["cidr_list"]))
+			p.SetDestcidrlist(setToStrings(rule["dest_cidr_list"]))
+
+			r, err := jobs(cs).client().Firewall.UpdateIpv6FirewallRule(p)
+			if err == nil {
+				err = jobs(cs).wait(r.JobID)
+			}
+			if err != nil {
+				errs = multierror.Append(errs, ruleError("updating", rule, err))
+				failed = true
+				break
+			}
+		}
+
+		// Keep the old rule on failure, so the next run will try again
+		if failed {
+			rules.Add(old)
+			continue
+		}
+
+		rule["uuids"] = uuids
+		rules.Add(rule)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func ipv6FirewallRuleKey(rule map[string]interface{}) string {
+	var ports []string
+	if ps, ok := rule["ports"].(*schema.Set); ok {
+		for _, port := range ps.List() {
+			ports = append(ports, port.(string))
+		}
+	}
+	sort.Strings(ports)
+
+	return fmt.Sprintf("%s/%s/%d/%d/%s",
+		strings.ToLower(rule["protocol"].(string)),
+		rule["traffic_type"].(string),
+		rule["icmp_type"].(int),
+		rule["icmp_code"].(int),
+		strings.Join(ports, ","),
+	)
+}
+
+func resourceCloudStackIPv6FirewallDelete(d *schema.ResourceData, meta interface{}) error {
+	// Create an empty rule set to hold all rules that where
+	// not deleted correctly
+	rules := resourceCloudStackIPv6Firewall().Schema["rule"].ZeroValue().(*schema.Set)
+
+	// Delete all rules
+	if ors := d.Get("rule").(*schema.Set); ors.Len() > 0 {
+		err := deleteIPv6FirewallRules(d, meta, rules, ors)
+
+		// We need to update this first to preserve the correct state
+		d.Set("rule", rules)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteIPv6FirewallRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, ors *schema.Set) error {
+	return applyFirewallRules(d, rules, ors, "deleting", func(sem jobSlots, rule map[string]interface{}) error {
+		return deleteIPv6FirewallRule(d, meta, sem, rule)
+	})
+}
+
+func deleteIPv6FirewallRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	return deleteFirewallRuleIDs(rule, func(id string) error {
+		// Create the parameter struct
+		p := cs.Firewall.NewDeleteIpv6FirewallRuleParams(id)
+
+		// Delete the rule
+		sem.take()
+		r, err := jobs(cs).client().Firewall.DeleteIpv6FirewallRule(p)
+		sem.release()
+		if err == nil {
+			err = jobs(cs).wait(r.JobID)
+		}
+
+		return err
+	})
+}
+
+// ipv6FirewallPortRules expands a rule into port rules, of which the scope
+// includes the traffic type and destinations. So only rules for the same
+// traffic type and destinations can be duplicates.
+func ipv6FirewallPortRules(rule map[string]interface{}) []portRule {
+	trafficType, _ := rule["traffic_type"].(string)
+
+	var suffix string
+	if destCidrList := setToStrings(rule["dest_cidr_list"]); len(destCidrList) > 0 {
+		suffix = "to " + strings.Join(destCidrList, ", ")
+	}
+
+	return expandPortRules(rule, trafficType, suffix, setToStrings(rule["cidr_list"]))
+}
+
+func resourceCloudStackIPv6FirewallImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Try to split the ID to extract the optional project name.
+	s := strings.SplitN(d.Id(), "/", 2)
+	if len(s) == 2 {
+		d.Set("project", s[0])
+	}
+
+	networkID := s[len(s)-1]
+	d.SetId(networkID)
+	d.Set("network_id", networkID)
+
+	// Get all the rules configured for this network
+	p := cs.Firewall.NewListIpv6FirewallRulesParams()
+	p.SetNetworkid(networkID)
+	p.SetListall(true)
+
+	if err := setProjectid(p, cs, d); err != nil {
+		return nil, err
+	}
+
+	l, err := cs.Firewall.ListIpv6FirewallRules(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create an empty schema.Set to hold all rules
+	rules := resourceCloudStackIPv6Firewall().Schema["rule"].ZeroValue().(*schema.Set)
+
+	// Group the TCP and UDP rules by traffic type, protocol and CIDR lists
+	grouped := newImportedRules()
+
+	for _, r := range l.Ipv6FirewallRules {
+		switch protocol := strings.ToLower(r.Protocol); protocol {
+		case "icmp", "all":
+			rule := map[string]interface{}{
+				"ports": &schema.Set{F: schema.HashString},
+				"uuids": map[string]interface{}{protocol: r.Id},
+			}
+			setIPv6FirewallRuleValues(rule, r)
+			if protocol == "icmp" {
+				rule["icmp_type"] = r.Icmptype
+				rule["icmp_code"] = r.Icmpcode
+			}
+			rules.Add(rule)
+		default:
+			key := fmt.Sprintf("%s/%s/%s/%s", strings.ToLower(r.Traffictype), protocol,
+				sortedCIDRList(r.Cidrlist), sortedCIDRList(r.Destcidrlist))
+			rule := grouped.group(key, func() map[string]interface{} {
+				rule := make(map[string]interface{})
+				setIPv6FirewallRuleValues(rule, r)
+				return rule
+			})
+			grouped.addPort(rule, "", r.Startport, r.Endport, r.Id)
+		}
+	}
+
+	for _, rule := range grouped.list() {
+		rules.Add(rule)
+	}
+
+	d.Set("rule", rules)
+
+	// Don't delete rules that are added to the network later on, unless
+	// the config says otherwise
+	d.Set("managed", managedFalse)
+	d.Set("parallelism", 2)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func verifyIPv6FirewallRuleParams(d *schema.ResourceData, pa portAliases, rule map[string]interface{}) error {
+	protocol := strings.ToLower(rule["protocol"].(string))
+
+	switch protocol {
+	case "icmp", "all":
+		if ports, _ := rule["ports"].(*schema.Set); ports.Len() > 0 {
+			return fmt.Errorf(
+				"Parameter ports is not required when using protocol %q", rule["protocol"].(string))
+		}
+	case "tcp", "udp":
+		ports, _ := rule["ports"].(*schema.Set)
+		if ports.Len() == 0 {
+			return fmt.Errorf(
+				"Parameter ports is a required parameter when using protocol %q", protocol)
+		}
+		for _, port := range ports.List() {
+			if _, _, err := pa.parsePortRange(port.(string)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf(
+			"%q is not a valid protocol. Valid options are 'tcp', 'udp', 'icmp' and 'all'",
+			rule["protocol"].(string))
+	}
+
+	for _, key := range []string{"cidr_list", "dest_cidr_list"} {
+		for _, cidr := range setToStrings(rule[key]) {
+			if !strings.Contains(cidr, ":") {
+				return fmt.Errorf("%q in %s is not an IPv6 CIDR", cidr, key)
+			}
+		}
+	}
+
+	return nil
+}
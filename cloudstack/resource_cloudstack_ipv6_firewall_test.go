@@ -0,0 +1,211 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackIPv6Firewall_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheckIPv6Support(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackIPv6FirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackIPv6Firewall_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackIPv6FirewallRulesExist("cloudstack_ipv6_firewall.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipv6_firewall.foo", "rule.#", "1"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipv6_firewall.foo", "rule.0.cidr_list.0", "2001:db8:ffff::/48"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipv6_firewall.foo", "rule.0.protocol", "tcp"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipv6_firewall.foo", "rule.0.traffic_type", "ingress"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackIPv6Firewall_update(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheckIPv6Support(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackIPv6FirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackIPv6Firewall_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackIPv6FirewallRulesExist("cloudstack_ipv6_firewall.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipv6_firewall.foo", "rule.#", "1"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackIPv6Firewall_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackIPv6FirewallRulesExist("cloudstack_ipv6_firewall.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_ipv6_firewall.foo", "rule.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackIPv6Firewall_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheckIPv6Support(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackIPv6FirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackIPv6Firewall_update,
+			},
+
+			{
+				ResourceName:      "cloudstack_ipv6_firewall.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackIPv6FirewallRulesExist(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No firewall ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+		for k, id := range rs.Primary.Attributes {
+			if !strings.Contains(k, ".uuids.") || strings.HasSuffix(k, ".uuids.%") {
+				continue
+			}
+
+			p := cs.Firewall.NewListIpv6FirewallRulesParams()
+			p.SetId(id)
+
+			l, err := cs.Firewall.ListIpv6FirewallRules(p)
+			if err != nil {
+				return err
+			}
+
+			if l.Count == 0 {
+				return fmt.Errorf("IPv6 firewall rule for %s not found", k)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackIPv6FirewallDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_ipv6_firewall" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No firewall ID is set")
+		}
+
+		for k, id := range rs.Primary.Attributes {
+			if !strings.Contains(k, ".uuids.") || strings.HasSuffix(k, ".uuids.%") {
+				continue
+			}
+
+			p := cs.Firewall.NewListIpv6FirewallRulesParams()
+			p.SetId(id)
+
+			l, err := cs.Firewall.ListIpv6FirewallRules(p)
+			if err == nil && l.Count > 0 {
+				return fmt.Errorf("IPv6 firewall rule %s still exists", id)
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackIPv6Firewall_basic = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network-ipv6"
+  display_text = "terraform-network-ipv6"
+  cidr = "10.1.2.0/24"
+  ip6cidr = "2001:db8::/64"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_ipv6_firewall" "foo" {
+  network_id = cloudstack_network.foo.id
+
+  rule {
+    cidr_list = ["2001:db8:ffff::/48"]
+    protocol = "tcp"
+    ports = ["80", "443"]
+  }
+}`
+
+const testAccCloudStackIPv6Firewall_update = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network-ipv6"
+  display_text = "terraform-network-ipv6"
+  cidr = "10.1.2.0/24"
+  ip6cidr = "2001:db8::/64"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_ipv6_firewall" "foo" {
+  network_id = cloudstack_network.foo.id
+
+  rule {
+    cidr_list = ["2001:db8:ffff::/48", "2001:db8:eeee::/48"]
+    protocol = "tcp"
+    ports = ["80", "443"]
+  }
+
+  rule {
+    traffic_type = "egress"
+    dest_cidr_list = ["::/0"]
+    protocol = "all"
+  }
+}`
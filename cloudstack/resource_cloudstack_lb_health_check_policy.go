@@ -0,0 +1,220 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackLBHealthCheckPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackLBHealthCheckPolicyCreate,
+		Read:   resourceCloudStackLBHealthCheckPolicyRead,
+		Update: resourceCloudStackLBHealthCheckPolicyUpdate,
+		Delete: resourceCloudStackLBHealthCheckPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"lb_rule_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"ping_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"interval": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"response_timeout": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"healthy_threshold": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"unhealthy_threshold": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"for_display": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackLBHealthCheckPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	lbruleid := d.Get("lb_rule_id").(string)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewCreateLBHealthCheckPolicyParams(lbruleid)
+
+	if pingPath, ok := d.GetOk("ping_path"); ok {
+		p.SetPingpath(pingPath.(string))
+	}
+
+	if description, ok := d.GetOk("description"); ok {
+		p.SetDescription(description.(string))
+	}
+
+	if interval, ok := d.GetOk("interval"); ok {
+		p.SetIntervaltime(interval.(int))
+	}
+
+	if timeout, ok := d.GetOk("response_timeout"); ok {
+		p.SetResponsetimeout(timeout.(int))
+	}
+
+	if threshold, ok := d.GetOk("healthy_threshold"); ok {
+		p.SetHealthythreshold(threshold.(int))
+	}
+
+	if threshold, ok := d.GetOk("unhealthy_threshold"); ok {
+		p.SetUnhealthythreshold(threshold.(int))
+	}
+
+	p.SetFordisplay(d.Get("for_display").(bool))
+
+	r, err := cs.LoadBalancer.CreateLBHealthCheckPolicy(p)
+	if err != nil {
+		return fmt.Errorf(
+			"Error creating health check policy for load balancer rule %s: %s", lbruleid, err)
+	}
+
+	if len(r.Healthcheckpolicy) == 0 {
+		return fmt.Errorf(
+			"Error creating health check policy for load balancer rule %s: no policy ID returned", lbruleid)
+	}
+
+	d.SetId(r.Healthcheckpolicy[0].Id)
+
+	return resourceCloudStackLBHealthCheckPolicyRead(d, meta)
+}
+
+func resourceCloudStackLBHealthCheckPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewListLBHealthCheckPoliciesParams()
+	p.SetId(d.Id())
+
+	l, err := cs.LoadBalancer.ListLBHealthCheckPolicies(p)
+	if err != nil {
+		return fmt.Errorf("Error retrieving health check policy %s: %s", d.Id(), err)
+	}
+
+	for _, policies := range l.LBHealthCheckPolicies {
+		for _, hc := range policies.Healthcheckpolicy {
+			if hc.Id != d.Id() {
+				continue
+			}
+
+			d.Set("lb_rule_id", policies.Lbruleid)
+			d.Set("ping_path", hc.Pingpath)
+			d.Set("description", hc.Description)
+			d.Set("interval", hc.Healthcheckinterval)
+			d.Set("response_timeout", hc.Responsetime)
+			d.Set("healthy_threshold", hc.Healthcheckthresshold)
+			d.Set("unhealthy_threshold", hc.Unhealthcheckthresshold)
+			d.Set("for_display", hc.Fordisplay)
+
+			return nil
+		}
+	}
+
+	log.Printf("[DEBUG] Health check policy %s does no longer exist", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func resourceCloudStackLBHealthCheckPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if d.HasChange("for_display") {
+		// Create a new parameter struct
+		p := cs.LoadBalancer.NewUpdateLBHealthCheckPolicyParams(d.Id())
+		p.SetFordisplay(d.Get("for_display").(bool))
+
+		_, err := cs.LoadBalancer.UpdateLBHealthCheckPolicy(p)
+		if err != nil {
+			return fmt.Errorf("Error updating health check policy %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceCloudStackLBHealthCheckPolicyRead(d, meta)
+}
+
+func resourceCloudStackLBHealthCheckPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewDeleteLBHealthCheckPolicyParams(d.Id())
+
+	if _, err := cs.LoadBalancer.DeleteLBHealthCheckPolicy(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting health check policy %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
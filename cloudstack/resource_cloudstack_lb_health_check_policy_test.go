@@ -0,0 +1,178 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackLBHealthCheckPolicy_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackLBHealthCheckPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackLBHealthCheckPolicy_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackLBHealthCheckPolicyExists(
+						"cloudstack_lb_health_check_policy.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_lb_health_check_policy.foo", "ping_path", "/health"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_lb_health_check_policy.foo", "interval", "10"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_lb_health_check_policy.foo", "healthy_threshold", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackLBHealthCheckPolicy_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackLBHealthCheckPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackLBHealthCheckPolicy_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_lb_health_check_policy.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackLBHealthCheckPolicyExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No health check policy ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		p := cs.LoadBalancer.NewListLBHealthCheckPoliciesParams()
+		p.SetId(rs.Primary.ID)
+
+		l, err := cs.LoadBalancer.ListLBHealthCheckPolicies(p)
+		if err != nil {
+			return err
+		}
+
+		for _, policies := range l.LBHealthCheckPolicies {
+			for _, hc := range policies.Healthcheckpolicy {
+				if hc.Id == rs.Primary.ID {
+					return nil
+				}
+			}
+		}
+
+		return fmt.Errorf("Health check policy not found")
+	}
+}
+
+func testAccCheckCloudStackLBHealthCheckPolicyDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_lb_health_check_policy" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No health check policy ID is set")
+		}
+
+		p := cs.LoadBalancer.NewListLBHealthCheckPoliciesParams()
+		p.SetId(rs.Primary.ID)
+
+		l, err := cs.LoadBalancer.ListLBHealthCheckPolicies(p)
+		if err != nil {
+			// The load balancer rule itself is gone as well
+			continue
+		}
+
+		for _, policies := range l.LBHealthCheckPolicies {
+			for _, hc := range policies.Healthcheckpolicy {
+				if hc.Id == rs.Primary.ID {
+					return fmt.Errorf("Health check policy %s still exists", rs.Primary.ID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackLBHealthCheckPolicy_basic = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  source_nat_ip = true
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_ipaddress" "foo" {
+  network_id = cloudstack_network.foo.id
+}
+
+resource "cloudstack_instance" "foobar1" {
+  name = "terraform-server1"
+  display_name = "terraform"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = cloudstack_network.foo.zone
+  expunge = true
+}
+
+resource "cloudstack_loadbalancer_rule" "foo" {
+  name = "terraform-lb"
+  ip_address_id = cloudstack_ipaddress.foo.id
+  algorithm = "roundrobin"
+  public_port = 80
+  private_port = 80
+  member_ids = [cloudstack_instance.foobar1.id]
+}
+
+resource "cloudstack_lb_health_check_policy" "foo" {
+  lb_rule_id = cloudstack_loadbalancer_rule.foo.id
+  ping_path = "/health"
+  interval = 10
+  response_timeout = 5
+  healthy_threshold = 3
+  unhealthy_threshold = 5
+}`
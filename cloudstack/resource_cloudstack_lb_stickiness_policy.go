@@ -0,0 +1,214 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudStackLBStickinessPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackLBStickinessPolicyCreate,
+		Read:   resourceCloudStackLBStickinessPolicyRead,
+		Update: resourceCloudStackLBStickinessPolicyUpdate,
+		Delete: resourceCloudStackLBStickinessPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"lb_rule_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"method": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"LbCookie", "AppCookie", "SourceBased",
+				}, false),
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"params": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"for_display": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackLBStickinessPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewCreateLBStickinessPolicyParams(
+		d.Get("lb_rule_id").(string),
+		d.Get("method").(string),
+		name,
+	)
+
+	if description, ok := d.GetOk("description"); ok {
+		p.SetDescription(description.(string))
+	}
+
+	if params, ok := d.GetOk("params"); ok {
+		m := make(map[string]string)
+		for k, v := range params.(map[string]interface{}) {
+			m[k] = v.(string)
+		}
+		p.SetParam(m)
+	}
+
+	p.SetFordisplay(d.Get("for_display").(bool))
+
+	r, err := cs.LoadBalancer.CreateLBStickinessPolicy(p)
+	if err != nil {
+		return fmt.Errorf("Error creating stickiness policy %s: %s", name, err)
+	}
+
+	// A load balancer rule has at most one stickiness policy, but the
+	// response still returns a list so match it on name to be sure
+	for _, sp := range r.Stickinesspolicy {
+		if sp.Name == name {
+			d.SetId(sp.Id)
+			break
+		}
+	}
+
+	if d.Id() == "" {
+		return fmt.Errorf("Error creating stickiness policy %s: no policy ID returned", name)
+	}
+
+	return resourceCloudStackLBStickinessPolicyRead(d, meta)
+}
+
+func resourceCloudStackLBStickinessPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewListLBStickinessPoliciesParams()
+	p.SetId(d.Id())
+
+	l, err := cs.LoadBalancer.ListLBStickinessPolicies(p)
+	if err != nil {
+		return fmt.Errorf("Error retrieving stickiness policy %s: %s", d.Id(), err)
+	}
+
+	for _, policies := range l.LBStickinessPolicies {
+		for _, sp := range policies.Stickinesspolicy {
+			if sp.Id != d.Id() {
+				continue
+			}
+
+			d.Set("lb_rule_id", policies.Lbruleid)
+			d.Set("name", sp.Name)
+			d.Set("method", sp.Methodname)
+			d.Set("description", sp.Description)
+			d.Set("for_display", sp.Fordisplay)
+
+			// CloudStack returns the defaults of all method parameters, so
+			// only track the ones that are configured (or all when importing)
+			configured := d.Get("params").(map[string]interface{})
+			params := make(map[string]interface{})
+			for k, v := range sp.Params {
+				if _, ok := configured[k]; ok || len(configured) == 0 {
+					params[k] = v
+				}
+			}
+			d.Set("params", params)
+
+			return nil
+		}
+	}
+
+	log.Printf("[DEBUG] Stickiness policy %s does no longer exist", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func resourceCloudStackLBStickinessPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if d.HasChange("for_display") {
+		// Create a new parameter struct
+		p := cs.LoadBalancer.NewUpdateLBStickinessPolicyParams(d.Id())
+		p.SetFordisplay(d.Get("for_display").(bool))
+
+		_, err := cs.LoadBalancer.UpdateLBStickinessPolicy(p)
+		if err != nil {
+			return fmt.Errorf(
+				"Error updating stickiness policy %s: %s", d.Get("name").(string), err)
+		}
+	}
+
+	return resourceCloudStackLBStickinessPolicyRead(d, meta)
+}
+
+func resourceCloudStackLBStickinessPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewDeleteLBStickinessPolicyParams(d.Id())
+
+	if _, err := cs.LoadBalancer.DeleteLBStickinessPolicy(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting stickiness policy %s: %s", d.Get("name").(string), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackLBStickinessPolicy_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackLBStickinessPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackLBStickinessPolicy_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackLBStickinessPolicyExists(
+						"cloudstack_lb_stickiness_policy.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_lb_stickiness_policy.foo", "name", "terraform-sticky"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_lb_stickiness_policy.foo", "method", "LbCookie"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_lb_stickiness_policy.foo", "params.cookie-name", "SRVID"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackLBStickinessPolicy_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackLBStickinessPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackLBStickinessPolicy_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_lb_stickiness_policy.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// All method parameters are read back when importing
+				ImportStateVerifyIgnore: []string{"params"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackLBStickinessPolicyExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No stickiness policy ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		p := cs.LoadBalancer.NewListLBStickinessPoliciesParams()
+		p.SetId(rs.Primary.ID)
+
+		l, err := cs.LoadBalancer.ListLBStickinessPolicies(p)
+		if err != nil {
+			return err
+		}
+
+		for _, policies := range l.LBStickinessPolicies {
+			for _, sp := range policies.Stickinesspolicy {
+				if sp.Id == rs.Primary.ID {
+					return nil
+				}
+			}
+		}
+
+		return fmt.Errorf("Stickiness policy not found")
+	}
+}
+
+func testAccCheckCloudStackLBStickinessPolicyDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_lb_stickiness_policy" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No stickiness policy ID is set")
+		}
+
+		p := cs.LoadBalancer.NewListLBStickinessPoliciesParams()
+		p.SetId(rs.Primary.ID)
+
+		l, err := cs.LoadBalancer.ListLBStickinessPolicies(p)
+		if err != nil {
+			// The load balancer rule itself is gone as well
+			continue
+		}
+
+		for _, policies := range l.LBStickinessPolicies {
+			for _, sp := range policies.Stickinesspolicy {
+				if sp.Id == rs.Primary.ID {
+					return fmt.Errorf("Stickiness policy %s still exists", rs.Primary.ID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackLBStickinessPolicy_basic = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  source_nat_ip = true
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_ipaddress" "foo" {
+  network_id = cloudstack_network.foo.id
+}
+
+resource "cloudstack_instance" "foobar1" {
+  name = "terraform-server1"
+  display_name = "terraform"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = cloudstack_network.foo.zone
+  expunge = true
+}
+
+resource "cloudstack_loadbalancer_rule" "foo" {
+  name = "terraform-lb"
+  ip_address_id = cloudstack_ipaddress.foo.id
+  algorithm = "roundrobin"
+  public_port = 80
+  private_port = 80
+  member_ids = [cloudstack_instance.foobar1.id]
+}
+
+resource "cloudstack_lb_stickiness_policy" "foo" {
+  lb_rule_id = cloudstack_loadbalancer_rule.foo.id
+  name = "terraform-sticky"
+  method = "LbCookie"
+
+  params = {
+    cookie-name = "SRVID"
+    mode = "insert"
+  }
+}`
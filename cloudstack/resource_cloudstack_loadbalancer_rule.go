@@ -91,21 +91,41 @@ func resourceCloudStackLoadBalancerRule() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
-				ForceNew: true,
 			},
 
 			"member_ids": {
-				Type:     schema.TypeSet,
-				Required: true,
-				ForceNew: false,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Set:      schema.HashString,
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Computed:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Set:          schema.HashString,
+				ExactlyOneOf: []string{"member_ids", "member"},
+			},
+
+			"member": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: []string{"member_ids", "member"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"virtual_machine_id": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"vm_ips": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+					},
+				},
 			},
 
 			"cidrlist": {
 				Type:     schema.TypeSet,
 				Optional: true,
-				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Set:      schema.HashString,
 			},
@@ -186,19 +206,31 @@ func resourceCloudStackLoadBalancerRuleCreate(d *schema.ResourceData, meta inter
 		}
 	}
 
-	// Create a new parameter struct
-	mp := cs.LoadBalancer.NewAssignToLoadBalancerRuleParams(r.Id)
+	if members, ok := d.GetOk("member"); ok {
+		err = updateLoadBalancerRuleMembers(
+			cs, r.Id, loadBalancerRuleMembers(members.(*schema.Set)), true)
+		if err != nil {
+			return err
+		}
+
+		return resourceCloudStackLoadBalancerRuleRead(d, meta)
+	}
 
 	var mbs []string
 	for _, id := range d.Get("member_ids").(*schema.Set).List() {
 		mbs = append(mbs, id.(string))
 	}
 
-	mp.SetVirtualmachineids(mbs)
+	// An explicitly empty list of members leaves the rule without members
+	if len(mbs) > 0 {
+		// Create a new parameter struct
+		mp := cs.LoadBalancer.NewAssignToLoadBalancerRuleParams(r.Id)
+		mp.SetVirtualmachineids(mbs)
 
-	_, err = cs.LoadBalancer.AssignToLoadBalancerRule(mp)
-	if err != nil {
-		return err
+		_, err = cs.LoadBalancer.AssignToLoadBalancerRule(mp)
+		if err != nil {
+			return err
+		}
 	}
 
 	return resourceCloudStackLoadBalancerRuleRead(d, meta)
@@ -252,6 +284,13 @@ func resourceCloudStackLoadBalancerRuleRead(d *schema.ResourceData, meta interfa
 
 	setValueOrID(d, "project", lb.Project, lb.Projectid)
 
+	// Only set the members with their guest IPs if the user specified them
+	if members, ok := d.GetOk("member"); ok {
+		if err := readLoadBalancerRuleMembers(cs, d, members.(*schema.Set)); err != nil {
+			return err
+		}
+	}
+
 	p := cs.LoadBalancer.NewListLoadBalancerRuleInstancesParams(d.Id())
 	l, err := cs.LoadBalancer.ListLoadBalancerRuleInstances(p)
 	if err != nil {
@@ -337,7 +376,8 @@ func resourceCloudStackLoadBalancerRuleUpdate(d *schema.ResourceData, meta inter
 		return err
 	}
 
-	if d.HasChange("name") || d.HasChange("description") || d.HasChange("algorithm") {
+	if d.HasChange("name") || d.HasChange("description") || d.HasChange("algorithm") ||
+		d.HasChange("protocol") || d.HasChange("cidrlist") {
 		name := d.Get("name").(string)
 
 		// Create new parameter struct
@@ -369,6 +409,25 @@ func resourceCloudStackLoadBalancerRuleUpdate(d *schema.ResourceData, meta inter
 			p.SetAlgorithm(algorithm)
 		}
 
+		if d.HasChange("protocol") {
+			log.Printf(
+				"[DEBUG] Protocol has changed for load balancer rule %s, starting update", name)
+
+			p.SetProtocol(d.Get("protocol").(string))
+		}
+
+		if d.HasChange("cidrlist") {
+			log.Printf(
+				"[DEBUG] CIDR list has changed for load balancer rule %s, starting update", name)
+
+			var cidrList []string
+			for _, cidr := range d.Get("cidrlist").(*schema.Set).List() {
+				cidrList = append(cidrList, cidr.(string))
+			}
+
+			p.SetCidrlist(cidrList)
+		}
+
 		_, err := cs.LoadBalancer.UpdateLoadBalancerRule(p)
 		if err != nil {
 			return fmt.Errorf(
@@ -377,19 +436,42 @@ func resourceCloudStackLoadBalancerRuleUpdate(d *schema.ResourceData, meta inter
 	}
 
 	if d.HasChange("certificate_id") {
-		p := cs.LoadBalancer.NewRemoveCertFromLoadBalancerParams(d.Id())
-		if _, err := cs.LoadBalancer.RemoveCertFromLoadBalancer(p); err != nil {
+		oldCertificateID, certificateID := d.GetChange("certificate_id")
+
+		// Rebind the rule to the new certificate, which allows certificates
+		// to be rotated without recreating the rule
+		if oldCertificateID.(string) != "" {
+			p := cs.LoadBalancer.NewRemoveCertFromLoadBalancerParams(d.Id())
+			if _, err := cs.LoadBalancer.RemoveCertFromLoadBalancer(p); err != nil {
+				return err
+			}
+		}
+
+		if certificateID.(string) != "" {
+			cp := cs.LoadBalancer.NewAssignCertToLoadBalancerParams(certificateID.(string), d.Id())
+			if _, err := cs.LoadBalancer.AssignCertToLoadBalancer(cp); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.HasChange("member") {
+		o, n := d.GetChange("member")
+		ombs := loadBalancerRuleMembers(o.(*schema.Set))
+		nmbs := loadBalancerRuleMembers(n.(*schema.Set))
+
+		// Remove stale members first, so a virtual machine can move from its
+		// primary IP to specific guest IPs (or the other way around)
+		if err := updateLoadBalancerRuleMembers(cs, d.Id(), ombs.difference(nmbs), false); err != nil {
 			return err
 		}
 
-		_, certificateID := d.GetChange("certificate_id")
-		cp := cs.LoadBalancer.NewAssignCertToLoadBalancerParams(certificateID.(string), d.Id())
-		if _, err := cs.LoadBalancer.AssignCertToLoadBalancer(cp); err != nil {
+		if err := updateLoadBalancerRuleMembers(cs, d.Id(), nmbs.difference(ombs), true); err != nil {
 			return err
 		}
 	}
 
-	if d.HasChange("member_ids") {
+	if _, ok := d.GetOk("member"); !ok && d.HasChange("member_ids") {
 		log.Printf("[DEBUG] Load balancer rule %s member_ids change detected", d.Id())
 
 		asgCheckParams := cs.AutoScale.NewListAutoScaleVmGroupsParams()
@@ -541,13 +623,164 @@ func verifyLoadBalancerRule(d *schema.ResourceData) error {
 		protocol := protocol.(string)
 
 		switch protocol {
-		case "tcp", "udp", "tcp-proxy":
+		case "tcp", "udp", "tcp-proxy", "ssl":
 			// These are supported
 		default:
 			return fmt.Errorf(
-				"%q is not a valid protocol. Valid options are 'tcp', 'udp' of 'tcp-proxy'", protocol)
+				"%q is not a valid protocol. Valid options are 'tcp', 'udp', 'tcp-proxy' or 'ssl'", protocol)
 		}
 	}
 
 	return nil
 }
+
+// loadBalancerMember is a single virtual machine and guest IP pair assigned to
+// a load balancer rule. An empty IP means the primary IP of the virtual machine.
+type loadBalancerMember struct {
+	vmid string
+	ip   string
+}
+
+type loadBalancerMembers map[loadBalancerMember]bool
+
+func (m loadBalancerMembers) difference(other loadBalancerMembers) loadBalancerMembers {
+	diff := make(loadBalancerMembers)
+	for member := range m {
+		if !other[member] {
+			diff[member] = true
+		}
+	}
+	return diff
+}
+
+func loadBalancerRuleMembers(s *schema.Set) loadBalancerMembers {
+	members := make(loadBalancerMembers)
+
+	for _, v := range s.List() {
+		member := v.(map[string]interface{})
+		vmid := member["virtual_machine_id"].(string)
+
+		ips := member["vm_ips"].(*schema.Set)
+		if ips.Len() == 0 {
+			members[loadBalancerMember{vmid: vmid}] = true
+			continue
+		}
+
+		for _, ip := range ips.List() {
+			members[loadBalancerMember{vmid: vmid, ip: ip.(string)}] = true
+		}
+	}
+
+	return members
+}
+
+// updateLoadBalancerRuleMembers assigns members to, or removes them from, a
+// load balancer rule. The vmidipmap can only hold a single IP per virtual
+// machine, so specific guest IPs are sent in as many calls as needed.
+func updateLoadBalancerRuleMembers(
+	cs *cloudstack.CloudStackClient, id string, members loadBalancerMembers, assign bool) error {
+	var vmids []string
+	var ipmaps []map[string]string
+
+	for member := range members {
+		if member.ip == "" {
+			vmids = append(vmids, member.vmid)
+			continue
+		}
+
+		added := false
+		for _, ipmap := range ipmaps {
+			if _, ok := ipmap[member.vmid]; !ok {
+				ipmap[member.vmid] = member.ip
+				added = true
+				break
+			}
+		}
+		if !added {
+			ipmaps = append(ipmaps, map[string]string{member.vmid: member.ip})
+		}
+	}
+
+	action := "removing"
+	if assign {
+		action = "adding"
+	}
+
+	if len(vmids) > 0 {
+		log.Printf("[DEBUG] Load balancer rule %s: %s members %v", id, action, vmids)
+
+		var err error
+		if assign {
+			p := cs.LoadBalancer.NewAssignToLoadBalancerRuleParams(id)
+			p.SetVirtualmachineids(vmids)
+			_, err = cs.LoadBalancer.AssignToLoadBalancerRule(p)
+		} else {
+			p := cs.LoadBalancer.NewRemoveFromLoadBalancerRuleParams(id)
+			p.SetVirtualmachineids(vmids)
+			_, err = cs.LoadBalancer.RemoveFromLoadBalancerRule(p)
+		}
+		if err != nil {
+			return fmt.Errorf("Error %s members of load balancer rule %s: %s", action, id, err)
+		}
+	}
+
+	for _, ipmap := range ipmaps {
+		log.Printf("[DEBUG] Load balancer rule %s: %s members %v", id, action, ipmap)
+
+		var err error
+		if assign {
+			p := cs.LoadBalancer.NewAssignToLoadBalancerRuleParams(id)
+			p.SetVmidipmap(ipmap)
+			_, err = cs.LoadBalancer.AssignToLoadBalancerRule(p)
+		} else {
+			p := cs.LoadBalancer.NewRemoveFromLoadBalancerRuleParams(id)
+			p.SetVmidipmap(ipmap)
+			_, err = cs.LoadBalancer.RemoveFromLoadBalancerRule(p)
+		}
+		if err != nil {
+			return fmt.Errorf("Error %s members of load balancer rule %s: %s", action, id, err)
+		}
+	}
+
+	return nil
+}
+
+func readLoadBalancerRuleMembers(
+	cs *cloudstack.CloudStackClient, d *schema.ResourceData, configured *schema.Set) error {
+	// Remember which virtual machines are configured with specific guest IPs,
+	// the others are balanced on their primary IP
+	withIPs := make(map[string]bool)
+	for _, v := range configured.List() {
+		member := v.(map[string]interface{})
+		if member["vm_ips"].(*schema.Set).Len() > 0 {
+			withIPs[member["virtual_machine_id"].(string)] = true
+		}
+	}
+
+	p := cs.LoadBalancer.NewListLoadBalancerRuleInstancesParams(d.Id())
+	p.SetLbvmips(true)
+
+	l, err := cs.LoadBalancer.ListLoadBalancerRuleInstances(p)
+	if err != nil {
+		return err
+	}
+
+	var members []interface{}
+	for _, i := range l.LBRuleVMIDIPs {
+		vmid := i.Loadbalancerruleinstance.Id
+
+		ips := []interface{}{}
+		if withIPs[vmid] {
+			for _, ip := range i.Lbvmipaddresses {
+				ips = append(ips, ip)
+			}
+		}
+
+		members = append(members, map[string]interface{}{
+			"virtual_machine_id": vmid,
+			"vm_ips":             schema.NewSet(schema.HashString, ips),
+		})
+	}
+
+	return d.Set("member", members)
+}
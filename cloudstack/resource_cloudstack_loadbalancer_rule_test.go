@@ -166,6 +166,28 @@ func TestAccCloudStackLoadBalancerRule_forceNew(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackLoadBalancerRule_member(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackLoadBalancerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackLoadBalancerRule_member,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackLoadBalancerRuleExist("cloudstack_loadbalancer_rule.foo", nil),
+					resource.TestCheckResourceAttr(
+						"cloudstack_loadbalancer_rule.foo", "member.#", "1"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_loadbalancer_rule.foo", "member.0.vm_ips.#", "1"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_loadbalancer_rule.foo", "member_ids.#", "1"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackLoadBalancerRule_vpc(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -487,3 +509,44 @@ resource "cloudstack_loadbalancer_rule" "foo" {
   member_ids = [cloudstack_instance.foobar1.id, cloudstack_instance.foobar2.id]
   cidrlist = ["20.0.0.0/8"]
 }`
+
+const testAccCloudStackLoadBalancerRule_member = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  source_nat_ip = true
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_ipaddress" "foo" {
+  network_id = cloudstack_network.foo.id
+}
+
+resource "cloudstack_instance" "foobar1" {
+  name = "terraform-server1"
+  display_name = "terraform"
+  service_offering= "Small Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = cloudstack_network.foo.zone
+  expunge = true
+}
+
+resource "cloudstack_secondary_ipaddress" "foo" {
+  virtual_machine_id = cloudstack_instance.foobar1.id
+}
+
+resource "cloudstack_loadbalancer_rule" "foo" {
+  name = "terraform-lb"
+  ip_address_id = cloudstack_ipaddress.foo.id
+  algorithm = "roundrobin"
+  public_port = 80
+  private_port = 80
+
+  member {
+    virtual_machine_id = cloudstack_instance.foobar1.id
+    vm_ips = [cloudstack_secondary_ipaddress.foo.ip_address]
+  }
+}`
@@ -51,7 +51,7 @@ func resourceCloudStackNetwork() *schema.Resource {
 		return value
 	}
 
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceCloudStackNetworkCreate,
 		Read:   resourceCloudStackNetworkRead,
 		Update: resourceCloudStackNetworkUpdate,
@@ -60,6 +60,8 @@ func resourceCloudStackNetwork() *schema.Resource {
 			State: importStatePassthrough,
 		},
 
+		Timeouts: restartTimeouts(),
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -161,6 +163,18 @@ func resourceCloudStackNetwork() *schema.Resource {
 				Required: true,
 			},
 
+			"public_mtu": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"private_mtu": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
 			"vlan": {
 				Type:     schema.TypeInt,
 				Optional: true,
@@ -213,6 +227,12 @@ func resourceCloudStackNetwork() *schema.Resource {
 			"tags": tagsSchema(),
 		},
 	}
+
+	for k, v := range restartSchema() {
+		r.Schema[k] = v
+	}
+
+	return r
 }
 
 func resourceCloudStackNetworkCreate(d *schema.ResourceData, meta interface{}) error {
@@ -298,6 +318,14 @@ func resourceCloudStackNetworkCreate(d *schema.ResourceData, meta interface{}) e
 		p.SetNetworkdomain(networkDomain.(string))
 	}
 
+	if publicMTU, ok := d.GetOk("public_mtu"); ok {
+		p.SetPublicmtu(publicMTU.(int))
+	}
+
+	if privateMTU, ok := d.GetOk("private_mtu"); ok {
+		p.SetPrivatemtu(privateMTU.(int))
+	}
+
 	if vlan, ok := d.GetOk("vlan"); ok {
 		p.SetVlan(strconv.Itoa(vlan.(int)))
 	}
@@ -420,6 +448,8 @@ func resourceCloudStackNetworkRead(d *schema.ResourceData, meta interface{}) err
 	d.Set("cidr", n.Cidr)
 	d.Set("gateway", n.Gateway)
 	d.Set("network_domain", n.Networkdomain)
+	d.Set("public_mtu", n.Publicmtu)
+	d.Set("private_mtu", n.Privatemtu)
 	d.Set("vpc_id", n.Vpcid)
 
 	// Always set IPv6 fields to detect drift when IPv6 is removed server-side
@@ -499,6 +529,15 @@ func resourceCloudStackNetworkUpdate(d *schema.ResourceData, meta interface{}) e
 		p.SetNetworkdomain(d.Get("network_domain").(string))
 	}
 
+	// Check if the MTUs are changed
+	if d.HasChange("public_mtu") {
+		p.SetPublicmtu(d.Get("public_mtu").(int))
+	}
+
+	if d.HasChange("private_mtu") {
+		p.SetPrivatemtu(d.Get("private_mtu").(int))
+	}
+
 	// Check if the network offering is changed
 	if d.HasChange("network_offering") {
 		// Retrieve the network_offering ID
@@ -535,6 +574,13 @@ func resourceCloudStackNetworkUpdate(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
+	// Restart the network if the restart trigger has changed
+	if d.HasChange("restart_trigger") {
+		if err := restartNetwork(cs, d); err != nil {
+			return err
+		}
+	}
+
 	return resourceCloudStackNetworkRead(d, meta)
 }
 
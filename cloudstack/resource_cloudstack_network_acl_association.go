@@ -0,0 +1,302 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackNetworkACLAssociation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackNetworkACLAssociationCreate,
+		Read:   resourceCloudStackNetworkACLAssociationRead,
+		Update: resourceCloudStackNetworkACLAssociationUpdate,
+		Delete: resourceCloudStackNetworkACLAssociationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackNetworkACLAssociationImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"acl_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"network_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"network_id", "gateway_id"},
+			},
+
+			"gateway_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"validate_rules": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"previous_acl_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackNetworkACLAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+	aclID := d.Get("acl_id").(string)
+
+	if d.Get("validate_rules").(bool) {
+		if err := verifyNetworkACLListRules(d, meta, aclID); err != nil {
+			return err
+		}
+	}
+
+	id := d.Get("network_id").(string)
+	if id == "" {
+		id = d.Get("gateway_id").(string)
+	}
+
+	// Remember the current ACL list so it can be restored when the
+	// association is deleted
+	previous, err := currentNetworkACLListID(d, meta, id)
+	if err != nil {
+		return err
+	}
+	d.Set("previous_acl_id", previous)
+
+	if err := replaceNetworkACLList(d, meta, id, aclID); err != nil {
+		return err
+	}
+
+	d.SetId(id)
+
+	return resourceCloudStackNetworkACLAssociationRead(d, meta)
+}
+
+func resourceCloudStackNetworkACLAssociationRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if _, ok := d.GetOk("gateway_id"); ok {
+		gw, count, err := cs.VPC.GetPrivateGatewayByID(d.Id())
+		if err != nil {
+			if count == 0 {
+				log.Printf("[DEBUG] Private gateway %s does no longer exist", d.Id())
+				d.SetId("")
+				return nil
+			}
+
+			return err
+		}
+
+		d.Set("acl_id", gw.Aclid)
+
+		return nil
+	}
+
+	n, count, err := cs.Network.GetNetworkByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Network %s does no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("network_id", n.Id)
+	d.Set("acl_id", n.Aclid)
+
+	return nil
+}
+
+func resourceCloudStackNetworkACLAssociationUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("acl_id") {
+		aclID := d.Get("acl_id").(string)
+
+		if d.Get("validate_rules").(bool) {
+			if err := verifyNetworkACLListRules(d, meta, aclID); err != nil {
+				return err
+			}
+		}
+
+		if err := replaceNetworkACLList(d, meta, d.Id(), aclID); err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackNetworkACLAssociationRead(d, meta)
+}
+
+func resourceCloudStackNetworkACLAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+	previous := d.Get("previous_acl_id").(string)
+	if previous == "" || previous == d.Get("acl_id").(string) {
+		return nil
+	}
+
+	// Restore the ACL list that was used before the association was created
+	err := replaceNetworkACLList(d, meta, d.Id(), previous)
+	if err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func resourceCloudStackNetworkACLAssociationImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Try to split the ID to extract the optional project name.
+	s := strings.SplitN(d.Id(), "/", 2)
+	if len(s) == 2 {
+		d.Set("project", s[0])
+	}
+
+	id := s[len(s)-1]
+	d.SetId(id)
+	d.Set("validate_rules", false)
+
+	// The ID is either the ID of a network or of a private gateway
+	_, count, err := cs.Network.GetNetworkByID(
+		id,
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err == nil {
+		d.Set("network_id", id)
+		return []*schema.ResourceData{d}, nil
+	}
+	if count != 0 {
+		return nil, err
+	}
+
+	_, count, err = cs.VPC.GetPrivateGatewayByID(id)
+	if err != nil {
+		if count == 0 {
+			return nil, fmt.Errorf("No network or private gateway with ID %s exists", id)
+		}
+		return nil, err
+	}
+	d.Set("gateway_id", id)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// currentNetworkACLListID returns the ID of the ACL list that is currently
+// used by a network or private gateway.
+func currentNetworkACLListID(d *schema.ResourceData, meta interface{}, id string) (string, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if _, ok := d.GetOk("gateway_id"); ok {
+		gw, _, err := cs.VPC.GetPrivateGatewayByID(id)
+		if err != nil {
+			return "", fmt.Errorf("Error retrieving private gateway %s: %s", id, err)
+		}
+		return gw.Aclid, nil
+	}
+
+	n, _, err := cs.Network.GetNetworkByID(
+		id,
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return "", fmt.Errorf("Error retrieving network %s: %s", id, err)
+	}
+
+	return n.Aclid, nil
+}
+
+func replaceNetworkACLList(d *schema.ResourceData, meta interface{}, id string, aclID string) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	p := cs.NetworkACL.NewReplaceNetworkACLListParams(aclID)
+	if _, ok := d.GetOk("gateway_id"); ok {
+		p.SetGatewayid(id)
+	} else {
+		p.SetNetworkid(id)
+	}
+
+	log.Printf("[DEBUG] Replacing the ACL list of %s with %s", id, aclID)
+
+	if _, err := cs.NetworkACL.ReplaceNetworkACLList(p); err != nil {
+		return fmt.Errorf("Error replacing ACL of %s with %s: %s", id, aclID, err)
+	}
+
+	return nil
+}
+
+// verifyNetworkACLListRules verifies that an ACL list is not empty and has at
+// least one rule that allows traffic, so attaching it will not cut off all
+// traffic to the network.
+func verifyNetworkACLListRules(d *schema.ResourceData, meta interface{}, aclID string) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	p := cs.NetworkACL.NewListNetworkACLsParams()
+	p.SetAclid(aclID)
+	p.SetListall(true)
+
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	l, err := cs.NetworkACL.ListNetworkACLs(p)
+	if err != nil {
+		return fmt.Errorf("Error retrieving the rules of ACL list %s: %s", aclID, err)
+	}
+
+	if l.Count == 0 {
+		return fmt.Errorf("ACL list %s has no rules, refusing to attach it", aclID)
+	}
+
+	for _, r := range l.NetworkACLs {
+		if strings.EqualFold(r.Action, "allow") {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("ACL list %s only has deny rules, refusing to attach it", aclID)
+}
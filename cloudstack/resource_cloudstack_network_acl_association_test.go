@@ -0,0 +1,213 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackNetworkACLAssociation_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackNetworkACLAssociation_basic("blue"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkACLAssociation(
+						"cloudstack_network_acl_association.foo", "cloudstack_network_acl.blue"),
+				),
+			},
+			{
+				Config: testAccCloudStackNetworkACLAssociation_basic("green"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkACLAssociation(
+						"cloudstack_network_acl_association.foo", "cloudstack_network_acl.green"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackNetworkACLAssociation_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackNetworkACLAssociation_basic("blue"),
+			},
+
+			{
+				ResourceName:            "cloudstack_network_acl_association.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"previous_acl_id"},
+			},
+		},
+	})
+}
+
+func TestAccCloudStackNetworkACLAssociation_empty(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCloudStackNetworkACLAssociation_empty,
+				ExpectError: regexp.MustCompile("has no rules"),
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackNetworkACLAssociation(n string, acl string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No network ACL association ID is set")
+		}
+
+		ars, ok := s.RootModule().Resources[acl]
+		if !ok {
+			return fmt.Errorf("Not found: %s", acl)
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		network, _, err := cs.Network.GetNetworkByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if network.Aclid != ars.Primary.ID {
+			return fmt.Errorf("Bad ACL: %s, expected: %s", network.Aclid, ars.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCloudStackNetworkACLAssociation_basic(acl string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_vpc" "foo" {
+  name = "terraform-vpc"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_network_acl" "blue" {
+  name = "blue"
+  vpc_id = cloudstack_vpc.foo.id
+}
+
+resource "cloudstack_network_acl_ruleset" "blue" {
+  acl_id = cloudstack_network_acl.blue.id
+
+  rule {
+    rule_number = 10
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "80"
+    traffic_type = "ingress"
+  }
+}
+
+resource "cloudstack_network_acl" "green" {
+  name = "green"
+  vpc_id = cloudstack_vpc.foo.id
+}
+
+resource "cloudstack_network_acl_ruleset" "green" {
+  acl_id = cloudstack_network_acl.green.id
+
+  rule {
+    rule_number = 10
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "https"
+    traffic_type = "ingress"
+  }
+}
+
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingForVpcNetworks"
+  vpc_id = cloudstack_vpc.foo.id
+  acl_id = cloudstack_network_acl.blue.id
+  zone = cloudstack_vpc.foo.zone
+
+  lifecycle {
+    ignore_changes = [acl_id]
+  }
+}
+
+resource "cloudstack_network_acl_association" "foo" {
+  network_id = cloudstack_network.foo.id
+  acl_id = cloudstack_network_acl_ruleset.%[1]s.acl_id
+  validate_rules = true
+}`, acl)
+}
+
+const testAccCloudStackNetworkACLAssociation_empty = `
+resource "cloudstack_vpc" "foo" {
+  name = "terraform-vpc"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_network_acl" "foo" {
+  name = "foo"
+  vpc_id = cloudstack_vpc.foo.id
+}
+
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingForVpcNetworks"
+  vpc_id = cloudstack_vpc.foo.id
+  zone = cloudstack_vpc.foo.zone
+
+  lifecycle {
+    ignore_changes = [acl_id]
+  }
+}
+
+resource "cloudstack_network_acl_association" "foo" {
+  network_id = cloudstack_network.foo.id
+  acl_id = cloudstack_network_acl.foo.id
+  validate_rules = true
+}`
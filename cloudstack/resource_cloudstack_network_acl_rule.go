@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"log"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -36,16 +35,24 @@ import (
 )
 
 func resourceCloudStackNetworkACLRule() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceCloudStackNetworkACLRuleCreate,
-		Read:   resourceCloudStackNetworkACLRuleRead,
-		Update: resourceCloudStackNetworkACLRuleUpdate,
-		Delete: resourceCloudStackNetworkACLRuleDelete,
+	r := &schema.Resource{
+		Create:      resourceCloudStackNetworkACLRuleCreate,
+		ReadContext: readReportingUnmanagedRules(resourceCloudStackNetworkACLRuleRead),
+		Update:      resourceCloudStackNetworkACLRuleUpdate,
+		Delete:      resourceCloudStackNetworkACLRuleDelete,
 		Importer: &schema.ResourceImporter{
 			State: resourceCloudStackNetworkACLRuleImport,
 		},
 		DeprecationMessage: "cloudstack_network_acl_rule is deprecated. Use cloudstack_network_acl_ruleset instead for better performance and in-place updates.",
 		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+			// Reject duplicate rules before any of them are created
+			if portRulesKnown(diff, "rule") {
+				pa := portAliasesOf(meta.(*cloudstack.CloudStackClient))
+				if err := checkPortRules(pa, aclPortRules(diff.Get("rule").([]interface{})), true); err != nil {
+					return err
+				}
+			}
+
 			// Force replacement for migration from deprecated 'ports' to 'port' field
 			if diff.HasChange("rule") {
 				oldRules, newRules := diff.GetChange("rule")
@@ -110,11 +117,9 @@ func resourceCloudStackNetworkACLRule() *schema.Resource {
 				ForceNew: true,
 			},
 
-			"managed": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
+			"managed": managedSchema(),
+
+			"unmanaged_rules": unmanagedRulesSchema(),
 
 			"rule": {
 				Type:     schema.TypeList,
@@ -201,6 +206,12 @@ func resourceCloudStackNetworkACLRule() *schema.Resource {
 			},
 		},
 	}
+
+	// The managed field was a bool before it also accepted "report"
+	r.SchemaVersion = 1
+	r.StateUpgraders = []schema.StateUpgrader{managedStateUpgradeV0(r)}
+
+	return r
 }
 
 func resourceCloudStackNetworkACLRuleCreate(d *schema.ResourceData, meta interface{}) error {
@@ -252,19 +263,15 @@ func createNetworkACLRules(d *schema.ResourceData, meta interface{}, rules *[]in
 	var wg sync.WaitGroup
 	wg.Add(len(nrs))
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
+	sem := make(jobSlots, d.Get("parallelism").(int))
 	for i, rule := range nrs {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}, index int) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			log.Printf("[DEBUG] Creating rule #%d: %+v", index+1, rule)
 
 			// Create a single rule
-			err := createNetworkACLRule(d, meta, rule)
+			err := createNetworkACLRule(d, meta, sem, rule)
 			if err != nil {
 				log.Printf("[ERROR] Failed to create rule #%d: %v", index+1, err)
 				mu.Lock()
@@ -276,8 +283,6 @@ func createNetworkACLRules(d *schema.ResourceData, meta interface{}, rules *[]in
 			} else {
 				log.Printf("[WARN] Rule #%d created but has no UUIDs", index+1)
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}), i)
 	}
 
@@ -299,19 +304,22 @@ func createNetworkACLRules(d *schema.ResourceData, meta interface{}, rules *[]in
 	return nil
 }
 
-func createNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
+func createNetworkACLRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 	uuids := rule["uuids"].(map[string]interface{})
 	log.Printf("[DEBUG] Creating network ACL rule with protocol=%s", rule["protocol"].(string))
 
 	// Make sure all required parameters are there
-	if err := verifyNetworkACLRuleParams(d, rule); err != nil {
+	if err := verifyNetworkACLRuleParams(d, portAliasesOf(cs), rule); err != nil {
 		log.Printf("[ERROR] Failed to verify rule parameters: %v", err)
 		return err
 	}
 
+	// Protocols can be given by name or by number
+	protocol, _ := aclProtocol(rule["protocol"].(string))
+
 	// Create a new parameter struct
-	p := cs.NetworkACL.NewCreateNetworkACLParams(rule["protocol"].(string))
+	p := cs.NetworkACL.NewCreateNetworkACLParams(protocol)
 	log.Printf("[DEBUG] Initialized CreateNetworkACLParams")
 
 	// If a rule ID is specified, set it
@@ -348,12 +356,12 @@ func createNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 	}
 
 	// If the protocol is ICMP set the needed ICMP parameters
-	if rule["protocol"].(string) == "icmp" {
+	if protocol == "icmp" {
 		p.SetIcmptype(rule["icmp_type"].(int))
 		p.SetIcmpcode(rule["icmp_code"].(int))
 		log.Printf("[DEBUG] Set icmp_type=%d, icmp_code=%d", rule["icmp_type"].(int), rule["icmp_code"].(int))
 
-		r, err := Retry(4, retryableACLCreationFunc(cs, p))
+		r, err := Retry(4, retryableACLCreationFunc(cs, sem, p))
 		if err != nil {
 			log.Printf("[ERROR] Failed to create ICMP rule: %v", err)
 			return err
@@ -363,9 +371,9 @@ func createNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 		log.Printf("[DEBUG] Created ICMP rule with ID=%s", r.(*cloudstack.CreateNetworkACLResponse).Id)
 	}
 
-	// If the protocol is ALL set the needed parameters
-	if rule["protocol"].(string) == "all" {
-		r, err := Retry(4, retryableACLCreationFunc(cs, p))
+	// If the protocol is ALL or a protocol without ports, create the rule as is
+	if protocol != "icmp" && protocol != "tcp" && protocol != "udp" {
+		r, err := Retry(4, retryableACLCreationFunc(cs, sem, p))
 		if err != nil {
 			log.Printf("[ERROR] Failed to create ALL rule: %v", err)
 			return err
@@ -376,7 +384,7 @@ func createNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 	}
 
 	// If protocol is TCP or UDP, create the rule (with or without port)
-	if rule["protocol"].(string) == "tcp" || rule["protocol"].(string) == "udp" {
+	if protocol == "tcp" || protocol == "udp" {
 		// Check if deprecated ports field is used and reject it
 		if portsSet, hasPortsSet := rule["ports"].(*schema.Set); hasPortsSet && portsSet.Len() > 0 {
 			log.Printf("[ERROR] Attempt to create rule with deprecated ports field")
@@ -390,32 +398,17 @@ func createNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 			log.Printf("[DEBUG] Processing single port for TCP/UDP rule: %s", portStr)
 
 			if _, ok := uuids[portStr]; !ok {
-				m := splitPorts.FindStringSubmatch(portStr)
-				if m == nil {
-					log.Printf("[ERROR] Invalid port format: %s", portStr)
-					return fmt.Errorf("%q is not a valid port value. Valid options are '80' or '80-90'", portStr)
-				}
-
-				startPort, err := strconv.Atoi(m[1])
+				startPort, endPort, err := portAliasesOf(cs).parsePortRange(portStr)
 				if err != nil {
-					log.Printf("[ERROR] Failed to parse start port %s: %v", m[1], err)
+					log.Printf("[ERROR] Invalid port format: %s", portStr)
 					return err
 				}
 
-				endPort := startPort
-				if m[2] != "" {
-					endPort, err = strconv.Atoi(m[2])
-					if err != nil {
-						log.Printf("[ERROR] Failed to parse end port %s: %v", m[2], err)
-						return err
-					}
-				}
-
 				p.SetStartport(startPort)
 				p.SetEndport(endPort)
 				log.Printf("[DEBUG] Set port start=%d, end=%d", startPort, endPort)
 
-				r, err := Retry(4, retryableACLCreationFunc(cs, p))
+				r, err := Retry(4, retryableACLCreationFunc(cs, sem, p))
 				if err != nil {
 					log.Printf("[ERROR] Failed to create TCP/UDP rule for port %s: %v", portStr, err)
 					return err
@@ -430,7 +423,7 @@ func createNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 		} else {
 			// No port specified - create rule for all ports
 			log.Printf("[DEBUG] No port specified for TCP/UDP rule, creating rule for all ports")
-			r, err := Retry(4, retryableACLCreationFunc(cs, p))
+			r, err := Retry(4, retryableACLCreationFunc(cs, sem, p))
 			if err != nil {
 				log.Printf("[ERROR] Failed to create TCP/UDP rule for all ports: %v", err)
 				return err
@@ -501,7 +494,7 @@ func processTCPUDPRule(rule map[string]interface{}, ruleMap map[string]*cloudsta
 		}
 
 		rule["action"] = strings.ToLower(r.Action)
-		rule["protocol"] = r.Protocol
+		rule["protocol"] = configuredACLProtocol(rule["protocol"].(string), r.Protocol)
 		rule["traffic_type"] = strings.ToLower(r.Traffictype)
 		rule["cidr_list"] = cidrs
 		rule["rule_number"] = r.Number
@@ -533,7 +526,7 @@ func processPortForRule(portStr string, rule map[string]interface{}, ruleMap map
 	}
 
 	rule["action"] = strings.ToLower(r.Action)
-	rule["protocol"] = r.Protocol
+	rule["protocol"] = configuredACLProtocol(rule["protocol"].(string), r.Protocol)
 	rule["traffic_type"] = strings.ToLower(r.Traffictype)
 	rule["cidr_list"] = cidrs
 	rule["rule_number"] = r.Number
@@ -604,7 +597,9 @@ func resourceCloudStackNetworkACLRuleRead(d *schema.ResourceData, meta interface
 			uuids := rule["uuids"].(map[string]interface{})
 			log.Printf("[DEBUG] Processing rule with protocol=%s, uuids=%+v", rule["protocol"].(string), uuids)
 
-			if rule["protocol"].(string) == "icmp" {
+			protocol, _ := aclProtocol(rule["protocol"].(string))
+
+			if protocol == "icmp" {
 				id, ok := uuids["icmp"]
 				if !ok {
 					log.Printf("[DEBUG] No ICMP UUID found, skipping rule")
@@ -630,7 +625,7 @@ func resourceCloudStackNetworkACLRuleRead(d *schema.ResourceData, meta interface
 
 				// Update the values
 				rule["action"] = strings.ToLower(r.Action)
-				rule["protocol"] = r.Protocol
+				rule["protocol"] = configuredACLProtocol(rule["protocol"].(string), r.Protocol)
 				rule["icmp_type"] = r.Icmptype
 				rule["icmp_code"] = r.Icmpcode
 				rule["traffic_type"] = strings.ToLower(r.Traffictype)
@@ -640,7 +635,7 @@ func resourceCloudStackNetworkACLRuleRead(d *schema.ResourceData, meta interface
 				log.Printf("[DEBUG] Added ICMP rule to state: %+v", rule)
 			}
 
-			if rule["protocol"].(string) == "all" {
+			if protocol != "icmp" && protocol != "tcp" && protocol != "udp" {
 				id, ok := uuids["all"]
 				if !ok {
 					log.Printf("[DEBUG] No ALL UUID found, skipping rule")
@@ -666,7 +661,7 @@ func resourceCloudStackNetworkACLRuleRead(d *schema.ResourceData, meta interface
 
 				// Update the values
 				rule["action"] = strings.ToLower(r.Action)
-				rule["protocol"] = r.Protocol
+				rule["protocol"] = configuredACLProtocol(rule["protocol"].(string), r.Protocol)
 				rule["traffic_type"] = strings.ToLower(r.Traffictype)
 				rule["cidr_list"] = cidrs
 				rule["rule_number"] = r.Number
@@ -674,7 +669,7 @@ func resourceCloudStackNetworkACLRuleRead(d *schema.ResourceData, meta interface
 				log.Printf("[DEBUG] Added ALL rule to state: %+v", rule)
 			}
 
-			if rule["protocol"].(string) == "tcp" || rule["protocol"].(string) == "udp" {
+			if protocol == "tcp" || protocol == "udp" {
 				uuids := rule["uuids"].(map[string]interface{})
 				processTCPUDPRule(rule, ruleMap, uuids, &rules)
 			}
@@ -682,8 +677,8 @@ func resourceCloudStackNetworkACLRuleRead(d *schema.ResourceData, meta interface
 	}
 
 	// If this is a managed firewall, add all unknown rules into dummy rules
-	managed := d.Get("managed").(bool)
-	if managed && len(ruleMap) > 0 {
+	managed := d.Get("managed").(string)
+	if managed == managedTrue && len(ruleMap) > 0 {
 		for uuid := range ruleMap {
 			// We need to create and add a dummy value to a list as the
 			// cidr_list is a required field and thus needs a value
@@ -702,13 +697,24 @@ func resourceCloudStackNetworkACLRuleRead(d *schema.ResourceData, meta interface
 		}
 	}
 
+	// If unknown rules are only reported, add them to the unmanaged rules
+	var unmanaged []map[string]interface{}
+	if managed == managedReport {
+		for _, r := range ruleMap {
+			unmanaged = append(unmanaged, aclUnmanagedRule(r))
+		}
+	}
+	if err := setUnmanagedRules(d, unmanaged); err != nil {
+		return err
+	}
+
 	if len(rules) > 0 {
 		log.Printf("[DEBUG] Setting %d rules in state", len(rules))
 		if err := d.Set("rule", rules); err != nil {
 			log.Printf("[ERROR] Failed to set rule attribute: %v", err)
 			return err
 		}
-	} else if !managed {
+	} else if managed == managedFalse {
 		log.Printf("[DEBUG] No rules found and not managed, clearing ID")
 		d.SetId("")
 	}
@@ -780,7 +786,11 @@ func deleteNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 		p := cs.NetworkACL.NewDeleteNetworkACLParams(id.(string))
 
 		// Delete the rule
-		if _, err := cs.NetworkACL.DeleteNetworkACL(p); err != nil {
+		r, err := jobs(cs).client().NetworkACL.DeleteNetworkACL(p)
+		if err == nil {
+			err = jobs(cs).wait(r.JobID)
+		}
+		if err != nil {
 
 			// This is a very poor way to be told the ID does no longer exist :(
 			if strings.Contains(err.Error(), fmt.Sprintf(
@@ -803,7 +813,7 @@ func deleteNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 }
 
 func verifyNetworkACLParams(d *schema.ResourceData) error {
-	managed := d.Get("managed").(bool)
+	managed := d.Get("managed").(string) != managedFalse
 	_, rules := d.GetOk("rule")
 
 	if !rules && !managed {
@@ -814,7 +824,7 @@ func verifyNetworkACLParams(d *schema.ResourceData) error {
 	return nil
 }
 
-func verifyNetworkACLRuleParams(d *schema.ResourceData, rule map[string]interface{}) error {
+func verifyNetworkACLRuleParams(d *schema.ResourceData, pa portAliases, rule map[string]interface{}) error {
 	log.Printf("[DEBUG] Verifying parameters for rule: %+v", rule)
 
 	if ruleNum, ok := rule["rule_number"]; ok && ruleNum != nil {
@@ -834,7 +844,12 @@ func verifyNetworkACLRuleParams(d *schema.ResourceData, rule map[string]interfac
 		return fmt.Errorf("Parameter action only accepts 'allow' or 'deny' as values")
 	}
 
-	protocol := rule["protocol"].(string)
+	// Protocols can be given by name or by number
+	protocol, err := aclProtocol(rule["protocol"].(string))
+	if err != nil {
+		log.Printf("[ERROR] Invalid protocol: %s", rule["protocol"].(string))
+		return err
+	}
 	log.Printf("[DEBUG] Validating protocol: %s", protocol)
 	switch protocol {
 	case "icmp":
@@ -865,22 +880,16 @@ func verifyNetworkACLRuleParams(d *schema.ResourceData, rule map[string]interfac
 		// Validate the new port field if used
 		if hasPort && portStr != "" {
 			log.Printf("[DEBUG] Found port for TCP/UDP: %s", portStr)
-			m := splitPorts.FindStringSubmatch(portStr)
-			if m == nil {
+			if _, _, err := pa.parsePortRange(portStr); err != nil {
 				log.Printf("[ERROR] Invalid port format: %s", portStr)
-				return fmt.Errorf(
-					"%q is not a valid port value. Valid options are '80' or '80-90'", portStr)
+				return err
 			}
 		} else {
 			log.Printf("[DEBUG] No port specified for TCP/UDP, allowing empty port")
 		}
 	default:
-		_, err := strconv.ParseInt(protocol, 0, 0)
-		if err != nil {
-			log.Printf("[ERROR] Invalid protocol: %s", protocol)
-			return fmt.Errorf(
-				"%q is not a valid protocol. Valid options are 'tcp', 'udp', 'icmp', 'all' or a valid protocol number", protocol)
-		}
+		// Protocol numbers don't need any additional tests
+		log.Printf("[DEBUG] Protocol number %s validated", protocol)
 	}
 
 	traffic := rule["traffic_type"].(string)
@@ -896,12 +905,20 @@ func verifyNetworkACLRuleParams(d *schema.ResourceData, rule map[string]interfac
 
 func retryableACLCreationFunc(
 	cs *cloudstack.CloudStackClient,
+	sem jobSlots,
 	p *cloudstack.CreateNetworkACLParams) func() (interface{}, error) {
 	return func() (interface{}, error) {
-		r, err := cs.NetworkACL.CreateNetworkACL(p)
+		// Submit the rule without waiting for it, the job is polled
+		// together with the jobs of the other rules
+		sem.take()
+		r, err := jobs(cs).client().NetworkACL.CreateNetworkACL(p)
+		sem.release()
 		if err != nil {
 			return nil, err
 		}
+		if err := jobs(cs).wait(r.JobID); err != nil {
+			return nil, err
+		}
 		return r, nil
 	}
 }
@@ -922,7 +939,7 @@ func resourceCloudStackNetworkACLRuleImport(d *schema.ResourceData, meta interfa
 	d.Set("acl_id", aclID)
 
 	log.Printf("[DEBUG] Setting managed=true for ACL list import")
-	d.Set("managed", true)
+	d.Set("managed", managedTrue)
 
 	return []*schema.ResourceData{d}, nil
 }
@@ -967,14 +984,14 @@ func performNormalRuleUpdates(d *schema.ResourceData, meta interface{}, cs *clou
 
 			newRuleMap := newRule.(map[string]interface{})
 			log.Printf("[DEBUG] Comparing old rule %+v with new rule %+v", oldRuleMap, newRuleMap)
-			if rulesMatch(oldRuleMap, newRuleMap) {
+			if rulesMatch(portAliasesOf(cs), oldRuleMap, newRuleMap) {
 				log.Printf("[DEBUG] Found matching new rule for old rule")
 
 				if oldUUIDs, ok := oldRuleMap["uuids"].(map[string]interface{}); ok {
 					newRuleMap["uuids"] = oldUUIDs
 				}
 
-				if ruleNeedsUpdate(oldRuleMap, newRuleMap) {
+				if ruleNeedsUpdate(portAliasesOf(cs), oldRuleMap, newRuleMap) {
 					log.Printf("[DEBUG] Rule needs updating")
 					if uuids, ok := oldRuleMap["uuids"].(map[string]interface{}); ok {
 						for _, uuid := range uuids {
@@ -1044,21 +1061,21 @@ func performNormalRuleUpdates(d *schema.ResourceData, meta interface{}, cs *clou
 	return nil
 }
 
-func rulesMatch(oldRule, newRule map[string]interface{}) bool {
-	if oldRule["protocol"].(string) != newRule["protocol"].(string) ||
+func rulesMatch(pa portAliases, oldRule, newRule map[string]interface{}) bool {
+	if !equivalentACLProtocols(oldRule["protocol"].(string), newRule["protocol"].(string)) ||
 		oldRule["traffic_type"].(string) != newRule["traffic_type"].(string) ||
 		oldRule["action"].(string) != newRule["action"].(string) {
 		return false
 	}
 
-	protocol := newRule["protocol"].(string)
+	protocol, _ := aclProtocol(newRule["protocol"].(string))
 
 	if protocol == "tcp" || protocol == "udp" {
 		oldPort, oldHasPort := oldRule["port"].(string)
 		newPort, newHasPort := newRule["port"].(string)
 
 		if oldHasPort && newHasPort {
-			return oldPort == newPort
+			return pa.equivalentPorts(oldPort, newPort)
 		}
 
 		if oldHasPort != newHasPort {
@@ -1081,13 +1098,13 @@ func rulesMatch(oldRule, newRule map[string]interface{}) bool {
 	}
 }
 
-func ruleNeedsUpdate(oldRule, newRule map[string]interface{}) bool {
+func ruleNeedsUpdate(pa portAliases, oldRule, newRule map[string]interface{}) bool {
 	if oldRule["action"].(string) != newRule["action"].(string) {
 		log.Printf("[DEBUG] Action changed: %s -> %s", oldRule["action"].(string), newRule["action"].(string))
 		return true
 	}
 
-	if oldRule["protocol"].(string) != newRule["protocol"].(string) {
+	if !equivalentACLProtocols(oldRule["protocol"].(string), newRule["protocol"].(string)) {
 		log.Printf("[DEBUG] Protocol changed: %s -> %s", oldRule["protocol"].(string), newRule["protocol"].(string))
 		return true
 	}
@@ -1112,7 +1129,7 @@ func ruleNeedsUpdate(oldRule, newRule map[string]interface{}) bool {
 		return true
 	}
 
-	protocol := newRule["protocol"].(string)
+	protocol, _ := aclProtocol(newRule["protocol"].(string))
 	switch protocol {
 	case "icmp":
 		if oldRule["icmp_type"].(int) != newRule["icmp_type"].(int) {
@@ -1126,7 +1143,7 @@ func ruleNeedsUpdate(oldRule, newRule map[string]interface{}) bool {
 	case "tcp", "udp":
 		oldPort, oldHasPort := oldRule["port"].(string)
 		newPort, newHasPort := newRule["port"].(string)
-		if oldHasPort != newHasPort || (oldHasPort && newHasPort && oldPort != newPort) {
+		if oldHasPort != newHasPort || (oldHasPort && newHasPort && !pa.equivalentPorts(oldPort, newPort)) {
 			log.Printf("[DEBUG] Port changed: %s -> %s", oldPort, newPort)
 			return true
 		}
@@ -1184,7 +1201,8 @@ func updateNetworkACLRule(cs *cloudstack.CloudStackClient, oldRule, newRule map[
 			p.SetReason(desc)
 		}
 
-		p.SetProtocol(newRule["protocol"].(string))
+		protocol, _ := aclProtocol(newRule["protocol"].(string))
+		p.SetProtocol(protocol)
 
 		p.SetTraffictype(newRule["traffic_type"].(string))
 
@@ -1194,7 +1212,6 @@ func updateNetworkACLRule(cs *cloudstack.CloudStackClient, oldRule, newRule map[
 			log.Printf("[DEBUG] Set rule_number=%d", ruleNum)
 		}
 
-		protocol := newRule["protocol"].(string)
 		switch protocol {
 		case "icmp":
 			if icmpType, ok := newRule["icmp_type"].(int); ok {
@@ -1207,25 +1224,18 @@ func updateNetworkACLRule(cs *cloudstack.CloudStackClient, oldRule, newRule map[
 			}
 		case "tcp", "udp":
 			if portStr, hasPort := newRule["port"].(string); hasPort && portStr != "" {
-				m := splitPorts.FindStringSubmatch(portStr)
-				if m != nil {
-					startPort, err := strconv.Atoi(m[1])
-					if err == nil {
-						endPort := startPort
-						if m[2] != "" {
-							if ep, err := strconv.Atoi(m[2]); err == nil {
-								endPort = ep
-							}
-						}
-						p.SetStartport(startPort)
-						p.SetEndport(endPort)
-						log.Printf("[DEBUG] Set port start=%d, end=%d", startPort, endPort)
-					}
+				if startPort, endPort, err := portAliasesOf(cs).parsePortRange(portStr); err == nil {
+					p.SetStartport(startPort)
+					p.SetEndport(endPort)
+					log.Printf("[DEBUG] Set port start=%d, end=%d", startPort, endPort)
 				}
 			}
 		}
 
-		_, err := cs.NetworkACL.UpdateNetworkACLItem(p)
+		r, err := jobs(cs).client().NetworkACL.UpdateNetworkACLItem(p)
+		if err == nil {
+			err = jobs(cs).wait(r.JobID)
+		}
 		if err != nil {
 			log.Printf("[ERROR] Failed to update ACL rule %s: %v", uuid.(string), err)
 			return err
@@ -23,10 +23,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/go-multierror"
@@ -34,11 +34,11 @@ import (
 )
 
 func resourceCloudStackNetworkACLRuleset() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceCloudStackNetworkACLRulesetCreate,
-		Read:   resourceCloudStackNetworkACLRulesetRead,
-		Update: resourceCloudStackNetworkACLRulesetUpdate,
-		Delete: resourceCloudStackNetworkACLRulesetDelete,
+	r := &schema.Resource{
+		Create:      resourceCloudStackNetworkACLRulesetCreate,
+		ReadContext: readReportingUnmanagedRules(resourceCloudStackNetworkACLRulesetRead),
+		Update:      resourceCloudStackNetworkACLRulesetUpdate,
+		Delete:      resourceCloudStackNetworkACLRulesetDelete,
 		Importer: &schema.ResourceImporter{
 			State: resourceCloudStackNetworkACLRulesetImport,
 		},
@@ -56,11 +56,9 @@ func resourceCloudStackNetworkACLRuleset() *schema.Resource {
 				ForceNew: true,
 			},
 
-			"managed": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
+			"managed": managedSchema(),
+
+			"unmanaged_rules": unmanagedRulesSchema(),
 
 			"project": {
 				Type:     schema.TypeString,
@@ -68,6 +66,18 @@ func resourceCloudStackNetworkACLRuleset() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"rules_csv": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"rule", "rules_json"},
+			},
+
+			"rules_json": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"rule", "rules_csv"},
+			},
+
 			"rule": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -138,20 +148,55 @@ func resourceCloudStackNetworkACLRuleset() *schema.Resource {
 			},
 		},
 	}
+
+	// The managed field was a bool before it also accepted "report"
+	r.SchemaVersion = 1
+	r.StateUpgraders = []schema.StateUpgrader{managedStateUpgradeV0(r)}
+
+	return r
 }
 
 func resourceCloudStackNetworkACLRulesetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
-	// Only apply this logic during updates, not creates
-	if d.Id() == "" {
-		return nil
-	}
+	pa := portAliasesOf(meta.(*cloudstack.CloudStackClient))
 
 	old, new := d.GetChange("rule")
 	oldSet := old.(*schema.Set)
 	newSet := new.(*schema.Set)
 
-	// If the sets are empty, nothing to do
-	if oldSet.Len() == 0 || newSet.Len() == 0 {
+	// When the rules are given as a CSV or JSON document, the rules from
+	// the document are used as the configured rules
+	document := false
+	for _, k := range []string{"rules_csv", "rules_json"} {
+		if !d.NewValueKnown(k) {
+			return d.SetNewComputed("rule")
+		}
+
+		if v, ok := d.GetOk(k); ok {
+			rules, err := parseACLRulesDocument(k, v.(string))
+			if err != nil {
+				return err
+			}
+
+			newSet = resourceCloudStackNetworkACLRuleset().Schema["rule"].ZeroValue().(*schema.Set)
+			for _, rule := range rules {
+				newSet.Add(rule)
+			}
+			document = true
+		}
+	}
+
+	// Reject duplicate rules before any of them are created
+	if document || portRulesKnown(d, "rule") {
+		if err := checkPortRules(pa, aclPortRules(newSet.List()), true); err != nil {
+			return err
+		}
+	}
+
+	// Only apply this logic during updates, not creates
+	if d.Id() == "" || oldSet.Len() == 0 || newSet.Len() == 0 {
+		if document {
+			return d.SetNew("rule", newSet)
+		}
 		return nil
 	}
 
@@ -177,7 +222,7 @@ func resourceCloudStackNetworkACLRulesetCustomizeDiff(ctx context.Context, d *sc
 	for ruleNum, newRule := range newMap {
 		oldRule, exists := oldMap[ruleNum]
 
-		if exists && compareACLRules(oldRule, newRule) {
+		if exists && compareACLRules(pa, oldRule, newRule) {
 			// Rule exists and is functionally identical - preserve the old rule
 			// (including its UUID) to prevent spurious diff
 			preservedSet.Add(oldRule)
@@ -192,9 +237,20 @@ func resourceCloudStackNetworkACLRulesetCustomizeDiff(ctx context.Context, d *sc
 	return d.SetNew("rule", preservedSet)
 }
 
-func compareACLRules(old, new map[string]interface{}) bool {
-	// Compare all fields except uuid (which is computed and may differ)
-	fields := []string{"rule_number", "action", "protocol", "icmp_type", "icmp_code", "port", "traffic_type", "description"}
+func compareACLRules(pa portAliases, old, new map[string]interface{}) bool {
+	// Protocols and ports may be given by name, so compare what they refer to
+	if !equivalentACLProtocols(old["protocol"].(string), new["protocol"].(string)) {
+		return false
+	}
+
+	oldPort, _ := old["port"].(string)
+	newPort, _ := new["port"].(string)
+	if !pa.equivalentPorts(oldPort, newPort) {
+		return false
+	}
+
+	// Compare all other fields except uuid (which is computed and may differ)
+	fields := []string{"rule_number", "action", "icmp_type", "icmp_code", "traffic_type", "description"}
 
 	for _, field := range fields {
 		oldVal := old[field]
@@ -233,7 +289,16 @@ func resourceCloudStackNetworkACLRulesetCreate(d *schema.ResourceData, meta inte
 		// Create an empty schema.Set to hold all rules
 		rules := resourceCloudStackNetworkACLRuleset().Schema["rule"].ZeroValue().(*schema.Set)
 
-		err := createACLRules(d, meta, rules, nrs)
+		// Rules from a CSV or JSON document are imported in bulk if possible
+		_, hasCSV := d.GetOk("rules_csv")
+		_, hasJSON := d.GetOk("rules_json")
+
+		var err error
+		if hasCSV || hasJSON {
+			err = importACLRules(d, meta, rules, nrs)
+		} else {
+			err = createACLRules(d, meta, rules, nrs)
+		}
 		if err != nil {
 			return err
 		}
@@ -252,17 +317,13 @@ func createACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set,
 	var wg sync.WaitGroup
 	wg.Add(nrs.Len())
 
-	sem := make(chan struct{}, 10)
+	sem := make(jobSlots, 10)
 	for _, rule := range nrs.List() {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Create a single rule
-			err := createACLRule(d, meta, rule)
+			err := createACLRule(d, meta, sem, rule)
 
 			// If we have a UUID, we need to save the rule
 			if uuid, ok := rule["uuid"].(string); ok && uuid != "" {
@@ -273,11 +334,9 @@ func createACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set,
 
 			if err != nil {
 				mu.Lock()
-				errs = multierror.Append(errs, err)
+				errs = multierror.Append(errs, fmt.Errorf("Error creating ACL rule %d: %s", rule["rule_number"], err))
 				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -286,15 +345,108 @@ func createACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set,
 	return errs.ErrorOrNil()
 }
 
-func createACLRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
+// importACLRules imports all rules at once using the importNetworkACL API
+// that was added in CloudStack 4.20, and falls back to creating the rules one
+// by one when the API is not available.
+func importACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, nrs *schema.Set) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	p := cs.Custom.NewCustomServiceParams()
+	p.SetParam("aclid", d.Get("acl_id").(string))
+
+	for i, doc := range aclRuleDocuments(portAliasesOf(cs), setToRuleList(nrs)) {
+		prefix := fmt.Sprintf("rules[%d].", i)
+
+		p.SetParam(prefix+"number", strconv.Itoa(doc.Number))
+		p.SetParam(prefix+"action", doc.Action)
+		p.SetParam(prefix+"cidrlist", doc.Cidrlist)
+		p.SetParam(prefix+"protocol", doc.Protocol)
+		p.SetParam(prefix+"traffictype", doc.Traffictype)
+
+		if doc.Startport != "" {
+			p.SetParam(prefix+"startport", doc.Startport)
+			p.SetParam(prefix+"endport", doc.Endport)
+		}
+		if doc.Icmptype != nil {
+			p.SetParam(prefix+"icmptype", strconv.Itoa(*doc.Icmptype))
+			p.SetParam(prefix+"icmpcode", strconv.Itoa(*doc.Icmpcode))
+		}
+		if doc.Reason != "" {
+			p.SetParam(prefix+"reason", doc.Reason)
+		}
+	}
+
+	var result map[string]interface{}
+	if err := cs.Custom.CustomRequest("importNetworkACL", p, &result); err != nil {
+		if strings.Contains(err.Error(), "does not exist or it is not available") {
+			log.Printf("[DEBUG] importNetworkACL is not supported, creating the rules of ACL %s one by one", d.Id())
+			return createACLRules(d, meta, rules, nrs)
+		}
+
+		return fmt.Errorf("Error importing the rules of ACL %s: %s", d.Id(), err)
+	}
+
+	// Get the UUIDs of the imported rules
+	numbers, err := listACLRuleNumbers(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var errs *multierror.Error
+	for _, rule := range nrs.List() {
+		rule := rule.(map[string]interface{})
+
+		uuid, ok := numbers[rule["rule_number"].(int)]
+		if !ok {
+			errs = multierror.Append(errs, fmt.Errorf(
+				"Rule %d was not imported into ACL %s", rule["rule_number"].(int), d.Id()))
+			continue
+		}
+
+		rule["uuid"] = uuid
+		rules.Add(rule)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// parseACLRulesDocument parses the rules_csv or rules_json document.
+func parseACLRulesDocument(key, document string) ([]map[string]interface{}, error) {
+	var rules []map[string]interface{}
+	var err error
+
+	if key == "rules_csv" {
+		rules, err = parseACLRulesCSV(document)
+	} else {
+		rules, err = parseACLRulesJSON(document)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s: %s", key, err)
+	}
+
+	return rules, nil
+}
+
+func setToRuleList(rules *schema.Set) []map[string]interface{} {
+	list := make([]map[string]interface{}, 0, rules.Len())
+	for _, rule := range rules.List() {
+		list = append(list, rule.(map[string]interface{}))
+	}
+	return list
+}
+
+func createACLRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 
 	// Make sure all required parameters are there
-	if err := verifyACLRuleParams(d, rule); err != nil {
+	if err := verifyACLRuleParams(d, portAliasesOf(cs), rule); err != nil {
 		return err
 	}
 
-	protocol := rule["protocol"].(string)
+	protocol, err := aclProtocol(rule["protocol"].(string))
+	if err != nil {
+		return err
+	}
 	action := rule["action"].(string)
 	trafficType := rule["traffic_type"].(string)
 
@@ -334,7 +486,7 @@ func createACLRule(d *schema.ResourceData, meta interface{}, rule map[string]int
 		p.SetIcmptype(icmpType)
 		p.SetIcmpcode(icmpCode)
 
-		r, err := Retry(4, retryableACLCreationFunc(cs, p))
+		r, err := Retry(4, retryableACLCreationFunc(cs, sem, p))
 		if err != nil {
 			return err
 		}
@@ -343,9 +495,9 @@ func createACLRule(d *schema.ResourceData, meta interface{}, rule map[string]int
 		return nil
 	}
 
-	// If the protocol is ALL set the needed parameters
-	if protocol == "all" {
-		r, err := Retry(4, retryableACLCreationFunc(cs, p))
+	// If the protocol is ALL or a protocol without ports, create the rule as is
+	if protocol != "tcp" && protocol != "udp" {
+		r, err := Retry(4, retryableACLCreationFunc(cs, sem, p))
 		if err != nil {
 			return err
 		}
@@ -355,44 +507,24 @@ func createACLRule(d *schema.ResourceData, meta interface{}, rule map[string]int
 	}
 
 	// If protocol is TCP or UDP, create the rule (with or without port)
-	if protocol == "tcp" || protocol == "udp" {
-		portStr, hasPort := rule["port"].(string)
-
-		if hasPort && portStr != "" {
-			// Handle single port
-			m := splitPorts.FindStringSubmatch(portStr)
-			if m == nil {
-				return fmt.Errorf("%q is not a valid port value. Valid options are '80' or '80-90'", portStr)
-			}
-
-			startPort, err := strconv.Atoi(m[1])
-			if err != nil {
-				return err
-			}
-
-			endPort := startPort
-			if m[2] != "" {
-				endPort, err = strconv.Atoi(m[2])
-				if err != nil {
-					return err
-				}
-			}
-
-			p.SetStartport(startPort)
-			p.SetEndport(endPort)
-		}
-
-		r, err := Retry(4, retryableACLCreationFunc(cs, p))
+	if portStr, hasPort := rule["port"].(string); hasPort && portStr != "" {
+		// Handle single port
+		startPort, endPort, err := portAliasesOf(cs).parsePortRange(portStr)
 		if err != nil {
 			return err
 		}
 
-		rule["uuid"] = r.(*cloudstack.CreateNetworkACLResponse).Id
-		return nil
+		p.SetStartport(startPort)
+		p.SetEndport(endPort)
+	}
+
+	r, err := Retry(4, retryableACLCreationFunc(cs, sem, p))
+	if err != nil {
+		return err
 	}
 
-	// If we reach here, it's an unsupported protocol (should have been caught by validation)
-	return fmt.Errorf("unsupported protocol %q. Valid protocols are: tcp, udp, icmp, all", protocol)
+	rule["uuid"] = r.(*cloudstack.CreateNetworkACLResponse).Id
+	return nil
 }
 
 // buildRuleFromAPI converts a CloudStack NetworkACL API response to a rule map
@@ -519,8 +651,8 @@ func resourceCloudStackNetworkACLRulesetRead(d *schema.ResourceData, meta interf
 
 	// If this is a managed resource, add all unknown rules to dummy rules
 	// This allows Terraform to detect them and trigger an update to delete them
-	managed := d.Get("managed").(bool)
-	if managed && len(ruleMap) > 0 {
+	managed := d.Get("managed").(string)
+	if managed == managedTrue && len(ruleMap) > 0 {
 		log.Printf("[DEBUG] Found %d out-of-band ACL rules for ACL %s", len(ruleMap), d.Id())
 		for uuid, r := range ruleMap {
 			log.Printf("[DEBUG] Adding dummy rule for out-of-band rule: uuid=%s, rule_number=%d", uuid, r.Number)
@@ -535,9 +667,21 @@ func resourceCloudStackNetworkACLRulesetRead(d *schema.ResourceData, meta interf
 		}
 	}
 
+	// If unknown rules are only reported, add them to the unmanaged rules
+	var unmanaged []map[string]interface{}
+	if managed == managedReport {
+		log.Printf("[DEBUG] Reporting %d out-of-band ACL rules for ACL %s", len(ruleMap), d.Id())
+		for _, r := range ruleMap {
+			unmanaged = append(unmanaged, aclUnmanagedRule(r))
+		}
+	}
+	if err := setUnmanagedRules(d, unmanaged); err != nil {
+		return err
+	}
+
 	if rules.Len() > 0 {
 		d.Set("rule", rules)
-	} else if !managed {
+	} else if managed == managedFalse {
 		d.SetId("")
 	}
 
@@ -548,74 +692,49 @@ func resourceCloudStackNetworkACLRulesetUpdate(d *schema.ResourceData, meta inte
 	// Check if the rule set as a whole has changed
 	if d.HasChange("rule") {
 		o, n := d.GetChange("rule")
-		oldSet := o.(*schema.Set)
-		newSet := n.(*schema.Set)
-
-		// Build maps of rules by rule_number for efficient lookup
-		oldRulesByNumber := make(map[int]map[string]interface{})
-		newRulesByNumber := make(map[int]map[string]interface{})
+		plan := planACLRuleChanges(portAliasesOf(meta.(*cloudstack.CloudStackClient)), o.(*schema.Set), n.(*schema.Set))
 
-		for _, rule := range oldSet.List() {
-			ruleMap := rule.(map[string]interface{})
-			ruleNum := ruleMap["rule_number"].(int)
-			oldRulesByNumber[ruleNum] = ruleMap
+		// We need to start with a rule set containing all the rules we
+		// already have. Any rules that are not deleted correctly and any
+		// newly created rules, will be added to this set to make sure we
+		// end up in a consistent state
+		rules := resourceCloudStackNetworkACLRuleset().Schema["rule"].ZeroValue().(*schema.Set)
+		for _, rule := range o.(*schema.Set).List() {
+			rules.Add(rule)
 		}
 
-		for _, rule := range newSet.List() {
-			ruleMap := rule.(map[string]interface{})
-			ruleNum := ruleMap["rule_number"].(int)
-			newRulesByNumber[ruleNum] = ruleMap
-		}
+		// First, update rules that changed in place. Each update is applied
+		// atomically by CloudStack, so the rule is never missing.
+		if len(plan.updates) > 0 {
+			err := updateACLRules(d, meta, rules, plan.updates)
 
-		// Categorize rules into: update, delete, create
-		var rulesToUpdate []*ruleUpdatePair
-		var rulesToDelete []map[string]interface{}
-		var rulesToCreate []map[string]interface{}
-
-		// Find rules to update or delete
-		for ruleNum, oldRule := range oldRulesByNumber {
-			if newRule, exists := newRulesByNumber[ruleNum]; exists {
-				// Rule exists in both old and new - check if it needs updating
-				if aclRuleNeedsUpdate(oldRule, newRule) {
-					rulesToUpdate = append(rulesToUpdate, &ruleUpdatePair{
-						oldRule: oldRule,
-						newRule: newRule,
-					})
-				}
-				// If no update needed, the rule stays as-is (UUID preserved)
-			} else {
-				// Rule only exists in old state - delete it
-				rulesToDelete = append(rulesToDelete, oldRule)
-			}
-		}
+			// We need to update this first to preserve the correct state
+			d.Set("rule", rules)
 
-		// Find rules to create
-		for ruleNum, newRule := range newRulesByNumber {
-			if _, exists := oldRulesByNumber[ruleNum]; !exists {
-				// Rule only exists in new state - create it
-				rulesToCreate = append(rulesToCreate, newRule)
+			if err != nil {
+				return err
 			}
 		}
 
-		// We need to start with a rule set containing all the rules we
-		// already have and want to keep. Any rules that are not deleted
-		// correctly and any newly created rules, will be added to this
-		// set to make sure we end up in a consistent state
-		rules := resourceCloudStackNetworkACLRuleset().Schema["rule"].ZeroValue().(*schema.Set)
+		// Second, put all renumbered and new rules in place, before any
+		// rule is deleted
+		if len(plan.renumbers) > 0 || len(plan.creates) > 0 {
+			err := placeACLRules(d, meta, rules, plan)
+
+			// We need to update this first to preserve the correct state
+			d.Set("rule", rules)
 
-		// Add all rules that will remain (either unchanged or updated)
-		for ruleNum := range newRulesByNumber {
-			if oldRule, exists := oldRulesByNumber[ruleNum]; exists {
-				// This rule will either be updated or kept as-is
-				// Start with the old rule (which has the UUID)
-				rules.Add(oldRule)
+			if err != nil {
+				return err
 			}
 		}
 
-		// First, delete rules that are no longer needed
-		if len(rulesToDelete) > 0 {
+		// Third, delete the rules that are no longer needed, now all their
+		// replacements exist
+		if len(plan.deletes) > 0 {
 			deleteSet := &schema.Set{F: rules.F}
-			for _, rule := range rulesToDelete {
+			for _, rule := range plan.deletes {
+				rules.Remove(rule)
 				deleteSet.Add(rule)
 			}
 			err := deleteACLRules(d, meta, rules, deleteSet)
@@ -628,48 +747,470 @@ func resourceCloudStackNetworkACLRulesetUpdate(d *schema.ResourceData, meta inte
 			}
 		}
 
-		// Second, update rules that have changed
-		if len(rulesToUpdate) > 0 {
-			err := updateACLRules(d, meta, rules, rulesToUpdate)
+		// Finally, give all rules their configured rule number, now the
+		// numbers of the deleted rules are available
+		err := renumberACLRules(d, meta, rules, plan.numbers)
 
-			// We need to update this first to preserve the correct state
-			d.Set("rule", rules)
+		// We need to update this first to preserve the correct state
+		d.Set("rule", rules)
 
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackNetworkACLRulesetRead(d, meta)
+}
+
+type ruleUpdatePair struct {
+	oldRule map[string]interface{}
+	newRule map[string]interface{}
+}
+
+// aclRulePlan describes how to get from the old to the new rules, while
+// keeping every rule that is still needed in place at all times.
+type aclRulePlan struct {
+	// Rules with the same number that can be updated in place
+	updates []*ruleUpdatePair
+
+	// Rules that are unchanged, except for their rule number
+	renumbers []*ruleUpdatePair
+
+	// Rules that need to be created or deleted
+	creates []map[string]interface{}
+	deletes []map[string]interface{}
+
+	// The configured rule number of each rule, by UUID
+	numbers map[string]int
+}
+
+func planACLRuleChanges(pa portAliases, oldSet, newSet *schema.Set) *aclRulePlan {
+	plan := &aclRulePlan{numbers: make(map[string]int)}
+
+	oldRules := make(map[int]map[string]interface{})
+	for _, rule := range oldSet.List() {
+		rule := rule.(map[string]interface{})
+		oldRules[rule["rule_number"].(int)] = rule
+	}
+
+	var newRules []map[string]interface{}
+	for _, rule := range newSet.List() {
+		rule := rule.(map[string]interface{})
+		number := rule["rule_number"].(int)
+
+		// Rules that didn't change at all are kept as-is
+		if oldRule, ok := oldRules[number]; ok && !aclRuleNeedsUpdate(pa, oldRule, rule) {
+			plan.numbers[oldRule["uuid"].(string)] = number
+			delete(oldRules, number)
+			continue
+		}
+
+		newRules = append(newRules, rule)
+	}
+
+	// Sort the remaining rules so the plan doesn't depend on the set order
+	sort.Slice(newRules, func(i, j int) bool {
+		return newRules[i]["rule_number"].(int) < newRules[j]["rule_number"].(int)
+	})
+
+	oldNumbers := make([]int, 0, len(oldRules))
+	for number := range oldRules {
+		oldNumbers = append(oldNumbers, number)
+	}
+	sort.Ints(oldNumbers)
+
+	// Rules that only moved, e.g. because a rule was inserted before them,
+	// only need a new rule number
+	var remaining []map[string]interface{}
+	for _, rule := range newRules {
+		matched := false
+		for _, number := range oldNumbers {
+			oldRule, ok := oldRules[number]
+			if !ok || aclRuleNeedsUpdate(pa, oldRule, rule) {
+				continue
+			}
+
+			plan.renumbers = append(plan.renumbers, &ruleUpdatePair{
+				oldRule: oldRule,
+				newRule: rule,
+			})
+			plan.numbers[oldRule["uuid"].(string)] = rule["rule_number"].(int)
+			delete(oldRules, number)
+			matched = true
+			break
+		}
+
+		if !matched {
+			remaining = append(remaining, rule)
+		}
+	}
+
+	// Rules with the same number and protocol are updated in place, any
+	// other rule is replaced
+	for _, rule := range remaining {
+		number := rule["rule_number"].(int)
+
+		oldRule, ok := oldRules[number]
+		if ok && equivalentACLProtocols(oldRule["protocol"].(string), rule["protocol"].(string)) {
+			plan.updates = append(plan.updates, &ruleUpdatePair{
+				oldRule: oldRule,
+				newRule: rule,
+			})
+			plan.numbers[oldRule["uuid"].(string)] = number
+			delete(oldRules, number)
+			continue
+		}
+
+		plan.creates = append(plan.creates, rule)
+	}
+
+	for _, number := range oldNumbers {
+		if oldRule, ok := oldRules[number]; ok {
+			plan.deletes = append(plan.deletes, oldRule)
+		}
+	}
+
+	return plan
+}
+
+// placeACLRules creates all new rules and renumbers all moved rules. When
+// the configured number of a rule is still in use by a rule that will be
+// deleted or moved later, the rule is placed directly before that rule
+// using MoveNetworkAclItem, and gets its configured number afterwards.
+func placeACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, plan *aclRulePlan) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	numbers, err := listACLRuleNumbers(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var pending []*ruleUpdatePair
+	pending = append(pending, plan.renumbers...)
+	for _, rule := range plan.creates {
+		pending = append(pending, &ruleUpdatePair{newRule: rule})
+	}
+
+	// Handle the highest numbers first, so rules moving down the list
+	// free up the numbers needed by the rules before them
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].newRule["rule_number"].(int) > pending[j].newRule["rule_number"].(int)
+	})
+
+	for progress := true; progress && len(pending) > 0; {
+		progress = false
+
+		var blocked []*ruleUpdatePair
+		for _, pair := range pending {
+			number := pair.newRule["rule_number"].(int)
+			if _, ok := numbers[number]; ok {
+				blocked = append(blocked, pair)
+				continue
+			}
+
+			if pair.oldRule == nil {
+				if err := createACLRule(d, meta, nil, pair.newRule); err != nil {
+					return err
+				}
+				rules.Add(pair.newRule)
+				plan.numbers[pair.newRule["uuid"].(string)] = number
+				numbers[number] = pair.newRule["uuid"].(string)
+			} else {
+				uuid := pair.oldRule["uuid"].(string)
+				if err := setACLRuleNumber(cs, uuid, number); err != nil {
+					return err
+				}
+				delete(numbers, pair.oldRule["rule_number"].(int))
+				numbers[number] = uuid
+				setACLRuleNumbers(rules, numbers)
+			}
+
+			progress = true
+		}
+
+		pending = blocked
+	}
+
+	for _, pair := range pending {
+		number := pair.newRule["rule_number"].(int)
+
+		uuid := ""
+		if pair.oldRule == nil {
+			// Create the rule with a free rule number first
+			free, err := freeACLRuleNumber(numbers)
 			if err != nil {
 				return err
 			}
+
+			rule := copyACLRule(pair.newRule)
+			rule["rule_number"] = free
+
+			if err := createACLRule(d, meta, nil, rule); err != nil {
+				return err
+			}
+			rules.Add(rule)
+
+			uuid = rule["uuid"].(string)
+			numbers[free] = uuid
+		} else {
+			uuid = pair.oldRule["uuid"].(string)
 		}
+		plan.numbers[uuid] = number
+
+		blocker, taken := numbers[number]
+		_, kept := plan.numbers[blocker]
 
-		// Finally, create new rules
-		if len(rulesToCreate) > 0 {
-			createSet := &schema.Set{F: rules.F}
-			for _, rule := range rulesToCreate {
-				createSet.Add(rule)
+		switch {
+		case !taken:
+			// The number was freed by one of the previous moves
+			if err := setACLRuleNumber(cs, uuid, number); err != nil {
+				return err
+			}
+		case !kept:
+			// The rule takes the place of a rule that will be deleted
+			if err := moveACLRule(cs, uuid, previousACLRule(numbers, uuid, blocker), blocker); err != nil {
+				return err
 			}
-			err := createACLRules(d, meta, rules, createSet)
+		default:
+			// The rule swaps places with another rule, so place it between
+			// the rules it should end up between
+			previous, next := neighbourACLRules(numbers, plan.numbers, uuid)
+			if err := moveACLRule(cs, uuid, previous, next); err != nil {
+				return err
+			}
+		}
 
-			// We need to update this first to preserve the correct state
-			d.Set("rule", rules)
+		// Moving a rule may renumber other rules as well
+		numbers, err = listACLRuleNumbers(d, meta)
+		if err != nil {
+			return err
+		}
+		setACLRuleNumbers(rules, numbers)
+	}
 
-			if err != nil {
+	return nil
+}
+
+// renumberACLRules gives all rules their configured rule number. Rules are
+// only renumbered to numbers that are not in use, so the order of the rules
+// doesn't change in between.
+func renumberACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, configured map[string]int) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	numbers, err := listACLRuleNumbers(d, meta)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]int, len(numbers))
+	for number, uuid := range numbers {
+		current[uuid] = number
+	}
+
+	var pending []string
+	for uuid, number := range configured {
+		if n, ok := current[uuid]; ok && n != number {
+			pending = append(pending, uuid)
+		}
+	}
+	sort.Strings(pending)
+
+	for progress := true; progress && len(pending) > 0; {
+		progress = false
+
+		var blocked []string
+		for _, uuid := range pending {
+			number := configured[uuid]
+			if _, ok := numbers[number]; ok {
+				blocked = append(blocked, uuid)
+				continue
+			}
+
+			if err := setACLRuleNumber(cs, uuid, number); err != nil {
+				setACLRuleNumbers(rules, numbers)
 				return err
 			}
+			delete(numbers, current[uuid])
+			numbers[number] = uuid
+			current[uuid] = number
+
+			progress = true
 		}
+
+		pending = blocked
 	}
 
-	return resourceCloudStackNetworkACLRulesetRead(d, meta)
+	setACLRuleNumbers(rules, numbers)
+
+	if len(pending) > 0 {
+		return fmt.Errorf(
+			"Unable to renumber ACL rules %s, their rule numbers are still in use",
+			strings.Join(pending, ", "))
+	}
+
+	return nil
 }
 
-type ruleUpdatePair struct {
-	oldRule map[string]interface{}
-	newRule map[string]interface{}
+// listACLRuleNumbers returns the UUIDs of all rules of the ACL by rule number.
+func listACLRuleNumbers(d *schema.ResourceData, meta interface{}) (map[int]string, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	p := cs.NetworkACL.NewListNetworkACLsParams()
+	p.SetAclid(d.Id())
+	p.SetListall(true)
+
+	if err := setProjectid(p, cs, d); err != nil {
+		return nil, err
+	}
+
+	l, err := cs.NetworkACL.ListNetworkACLs(p)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing rules of ACL %s: %s", d.Id(), err)
+	}
+
+	numbers := make(map[int]string, l.Count)
+	for _, r := range l.NetworkACLs {
+		numbers[r.Number] = r.Id
+	}
+
+	return numbers, nil
+}
+
+// setACLRuleNumbers updates the rule numbers of the rules in the set to
+// match the given rule numbers.
+func setACLRuleNumbers(rules *schema.Set, numbers map[int]string) {
+	current := make(map[string]int, len(numbers))
+	for number, uuid := range numbers {
+		current[uuid] = number
+	}
+
+	for _, rule := range rules.List() {
+		rule := rule.(map[string]interface{})
+
+		number, ok := current[rule["uuid"].(string)]
+		if !ok || number == rule["rule_number"].(int) {
+			continue
+		}
+
+		// The rule needs to be removed before changing it, as the hash
+		// of the rule changes with its number
+		rules.Remove(rule)
+		rule["rule_number"] = number
+		rules.Add(rule)
+	}
+}
+
+func setACLRuleNumber(cs *cloudstack.CloudStackClient, uuid string, number int) error {
+	p := cs.NetworkACL.NewUpdateNetworkACLItemParams(uuid)
+	p.SetNumber(number)
+
+	r, err := jobs(cs).client().NetworkACL.UpdateNetworkACLItem(p)
+	if err == nil {
+		err = jobs(cs).wait(r.JobID)
+	}
+	if err != nil {
+		return fmt.Errorf("Error changing the rule number of ACL rule %s to %d: %s", uuid, number, err)
+	}
+
+	return nil
+}
+
+// moveACLRule moves a rule in between the given rules. CloudStack renumbers
+// the rules as needed.
+func moveACLRule(cs *cloudstack.CloudStackClient, uuid, previous, next string) error {
+	p := cs.NetworkACL.NewMoveNetworkAclItemParams(uuid)
+	if previous != "" {
+		p.SetPreviousaclruleid(previous)
+	}
+	if next != "" {
+		p.SetNextaclruleid(next)
+	}
+
+	if _, err := cs.NetworkACL.MoveNetworkAclItem(p); err != nil {
+		return fmt.Errorf("Error moving ACL rule %s: %s", uuid, err)
+	}
+
+	return nil
+}
+
+// previousACLRule returns the rule that currently comes before the given
+// next rule, ignoring the rule that is being moved.
+func previousACLRule(numbers map[int]string, uuid, next string) string {
+	nextNumber := -1
+	for number, id := range numbers {
+		if id == next {
+			nextNumber = number
+			break
+		}
+	}
+
+	previous := ""
+	previousNumber := -1
+	for number, id := range numbers {
+		if id != uuid && number < nextNumber && number > previousNumber {
+			previous = id
+			previousNumber = number
+		}
+	}
+
+	return previous
+}
+
+// neighbourACLRules returns the existing rules the given rule should end up
+// between, based on the configured rule numbers.
+func neighbourACLRules(numbers map[int]string, configured map[string]int, uuid string) (string, string) {
+	number := configured[uuid]
+
+	previous, next := "", ""
+	previousNumber, nextNumber := -1, 65536
+	for _, id := range numbers {
+		n, ok := configured[id]
+		if !ok || id == uuid {
+			continue
+		}
+
+		if n < number && n > previousNumber {
+			previous = id
+			previousNumber = n
+		}
+		if n > number && n < nextNumber {
+			next = id
+			nextNumber = n
+		}
+	}
+
+	return previous, next
+}
+
+// freeACLRuleNumber returns a rule number after all rules that are in use.
+func freeACLRuleNumber(numbers map[int]string) (int, error) {
+	highest := 0
+	for number := range numbers {
+		if number > highest {
+			highest = number
+		}
+	}
+
+	if highest >= 65535 {
+		return 0, fmt.Errorf("Unable to find a free ACL rule number")
+	}
+
+	return highest + 1, nil
+}
+
+func copyACLRule(rule map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(rule))
+	for k, v := range rule {
+		c[k] = v
+	}
+	return c
 }
 
 func resourceCloudStackNetworkACLRulesetDelete(d *schema.ResourceData, meta interface{}) error {
-	// If managed=false, don't delete any rules - just remove from state
-	managed := d.Get("managed").(bool)
-	if !managed {
-		log.Printf("[DEBUG] Managed=false, not deleting ACL rules for %s", d.Id())
+	// Unless managed=true, don't delete any rules - just remove from state
+	managed := d.Get("managed").(string)
+	if managed != managedTrue {
+		log.Printf("[DEBUG] Managed=%s, not deleting ACL rules for %s", managed, d.Id())
 		return nil
 	}
 
@@ -699,17 +1240,13 @@ func deleteACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set,
 	var wg sync.WaitGroup
 	wg.Add(ors.Len())
 
-	sem := make(chan struct{}, 10)
+	sem := make(jobSlots, 10)
 	for _, rule := range ors.List() {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Delete a single rule
-			err := deleteACLRule(d, meta, rule)
+			err := deleteACLRule(d, meta, sem, rule)
 
 			// If we have a UUID, we need to save the rule
 			if uuid, ok := rule["uuid"].(string); ok && uuid != "" {
@@ -720,11 +1257,9 @@ func deleteACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set,
 
 			if err != nil {
 				mu.Lock()
-				errs = multierror.Append(errs, err)
+				errs = multierror.Append(errs, fmt.Errorf("Error deleting ACL rule %d: %s", rule["rule_number"], err))
 				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -733,14 +1268,20 @@ func deleteACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set,
 	return errs.ErrorOrNil()
 }
 
-func deleteACLRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
+func deleteACLRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 
 	// Create the parameter struct
 	p := cs.NetworkACL.NewDeleteNetworkACLParams(rule["uuid"].(string))
 
 	// Delete the rule
-	if _, err := cs.NetworkACL.DeleteNetworkACL(p); err != nil {
+	sem.take()
+	r, err := jobs(cs).client().NetworkACL.DeleteNetworkACL(p)
+	sem.release()
+	if err == nil {
+		err = jobs(cs).wait(r.JobID)
+	}
+	if err != nil {
 		// This is a very poor way to be told the ID does no longer exist :(
 		if !strings.Contains(err.Error(), fmt.Sprintf(
 			"Invalid parameter id value=%s due to incorrect long value format, "+
@@ -762,17 +1303,13 @@ func updateACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set,
 	var wg sync.WaitGroup
 	wg.Add(len(updatePairs))
 
-	sem := make(chan struct{}, 10)
+	sem := make(jobSlots, 10)
 	for _, pair := range updatePairs {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(pair *ruleUpdatePair) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Update a single rule
-			err := updateACLRule(d, meta, pair.oldRule, pair.newRule)
+			err := updateACLRule(d, meta, sem, pair.oldRule, pair.newRule)
 
 			// If we have a UUID, we need to save the updated rule
 			if uuid, ok := pair.oldRule["uuid"].(string); ok && uuid != "" {
@@ -788,11 +1325,9 @@ func updateACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set,
 
 			if err != nil {
 				mu.Lock()
-				errs = multierror.Append(errs, err)
+				errs = multierror.Append(errs, fmt.Errorf("Error updating ACL rule %d: %s", pair.oldRule["rule_number"], err))
 				mu.Unlock()
 			}
-
-			<-sem
 		}(pair)
 	}
 
@@ -801,7 +1336,7 @@ func updateACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set,
 	return errs.ErrorOrNil()
 }
 
-func updateACLRule(d *schema.ResourceData, meta interface{}, oldRule, newRule map[string]interface{}) error {
+func updateACLRule(d *schema.ResourceData, meta interface{}, sem jobSlots, oldRule, newRule map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 
 	uuid := oldRule["uuid"].(string)
@@ -811,34 +1346,6 @@ func updateACLRule(d *schema.ResourceData, meta interface{}, oldRule, newRule ma
 
 	log.Printf("[DEBUG] Updating ACL rule with UUID: %s", uuid)
 
-	// If the protocol changed, we need to delete and recreate the rule
-	// because the CloudStack API doesn't properly clear protocol-specific fields
-	// (e.g., ports when changing from TCP to ICMP)
-	if oldRule["protocol"].(string) != newRule["protocol"].(string) {
-		log.Printf("[DEBUG] Protocol changed, using delete+create approach for rule %s", uuid)
-
-		// Delete the old rule
-		p := cs.NetworkACL.NewDeleteNetworkACLParams(uuid)
-		if _, err := cs.NetworkACL.DeleteNetworkACL(p); err != nil {
-			// Ignore "does not exist" errors
-			if !strings.Contains(err.Error(), fmt.Sprintf(
-				"Invalid parameter id value=%s due to incorrect long value format, "+
-					"or entity does not exist", uuid)) {
-				return fmt.Errorf("failed to delete rule during protocol change: %w", err)
-			}
-		}
-
-		// Create the new rule with the new protocol
-		if err := createACLRule(d, meta, newRule); err != nil {
-			return fmt.Errorf("failed to create rule during protocol change: %w", err)
-		}
-
-		// The new UUID is now in newRule["uuid"], copy it to oldRule so it gets saved
-		oldRule["uuid"] = newRule["uuid"]
-
-		return nil
-	}
-
 	// Create the parameter struct
 	p := cs.NetworkACL.NewUpdateNetworkACLItemParams(uuid)
 
@@ -858,7 +1365,11 @@ func updateACLRule(d *schema.ResourceData, meta interface{}, oldRule, newRule ma
 	}
 
 	// Set the protocol
-	p.SetProtocol(newRule["protocol"].(string))
+	protocol, err := aclProtocol(newRule["protocol"].(string))
+	if err != nil {
+		return err
+	}
+	p.SetProtocol(protocol)
 
 	// Set the traffic type
 	p.SetTraffictype(newRule["traffic_type"].(string))
@@ -866,7 +1377,6 @@ func updateACLRule(d *schema.ResourceData, meta interface{}, oldRule, newRule ma
 	// Set the rule number
 	p.SetNumber(newRule["rule_number"].(int))
 
-	protocol := newRule["protocol"].(string)
 	switch protocol {
 	case "icmp":
 		// icmp_type and icmp_code default to -1 (all) in the schema
@@ -880,26 +1390,21 @@ func updateACLRule(d *schema.ResourceData, meta interface{}, oldRule, newRule ma
 		// Don't set ports or ICMP fields for "all" protocol
 	case "tcp", "udp":
 		if portStr, hasPort := newRule["port"].(string); hasPort && portStr != "" {
-			m := splitPorts.FindStringSubmatch(portStr)
-			if m != nil {
-				startPort, err := strconv.Atoi(m[1])
-				if err == nil {
-					endPort := startPort
-					if m[2] != "" {
-						if ep, err := strconv.Atoi(m[2]); err == nil {
-							endPort = ep
-						}
-					}
-					p.SetStartport(startPort)
-					p.SetEndport(endPort)
-				}
+			if startPort, endPort, err := portAliasesOf(cs).parsePortRange(portStr); err == nil {
+				p.SetStartport(startPort)
+				p.SetEndport(endPort)
 			}
 		}
 		// If port is empty, don't set start/end port - CloudStack will handle "all ports"
 	}
 
 	// Execute the update
-	_, err := cs.NetworkACL.UpdateNetworkACLItem(p)
+	sem.take()
+	r, err := jobs(cs).client().NetworkACL.UpdateNetworkACLItem(p)
+	sem.release()
+	if err == nil {
+		err = jobs(cs).wait(r.JobID)
+	}
 	if err != nil {
 		log.Printf("[ERROR] Failed to update ACL rule %s: %v", uuid, err)
 		return err
@@ -909,7 +1414,7 @@ func updateACLRule(d *schema.ResourceData, meta interface{}, oldRule, newRule ma
 	return nil
 }
 
-func verifyACLRuleParams(d *schema.ResourceData, rule map[string]interface{}) error {
+func verifyACLRuleParams(d *schema.ResourceData, pa portAliases, rule map[string]interface{}) error {
 	ruleNumber := rule["rule_number"].(int)
 	if ruleNumber < 1 || ruleNumber > 65535 {
 		return fmt.Errorf("rule_number must be between 1 and 65535, got: %d", ruleNumber)
@@ -920,39 +1425,39 @@ func verifyACLRuleParams(d *schema.ResourceData, rule map[string]interface{}) er
 		return fmt.Errorf("action must be 'allow' or 'deny', got: %s", action)
 	}
 
-	protocol := rule["protocol"].(string)
+	// Protocols can be given by name or by number
+	protocol, err := aclProtocol(rule["protocol"].(string))
+	if err != nil {
+		return err
+	}
+
 	switch protocol {
 	case "icmp":
 		// icmp_type and icmp_code are optional - they default to -1 (all) if not specified
-	case "all":
-		// No additional validation needed
 	case "tcp", "udp":
 		// Port is optional
 		if portStr, ok := rule["port"].(string); ok && portStr != "" {
-			m := splitPorts.FindStringSubmatch(portStr)
-			if m == nil {
-				return fmt.Errorf("%q is not a valid port value. Valid options are '80' or '80-90'", portStr)
+			if _, _, err := pa.parsePortRange(portStr); err != nil {
+				return err
 			}
 		}
 	default:
-		// Reject numeric protocols - CloudStack API expects protocol names
-		if _, err := strconv.Atoi(protocol); err == nil {
-			return fmt.Errorf("numeric protocols are not supported, use protocol names instead (tcp, udp, icmp, all). Got: %s", protocol)
+		// Other protocols don't use ports
+		if portStr, ok := rule["port"].(string); ok && portStr != "" {
+			return fmt.Errorf("port can only be set when using protocol 'tcp' or 'udp', got protocol: %s", rule["protocol"].(string))
 		}
-		// If not a number, it's an unsupported protocol name
-		return fmt.Errorf("%q is not a valid protocol. Valid options are 'tcp', 'udp', 'icmp', 'all'", protocol)
 	}
 
 	return nil
 }
 
-func aclRuleNeedsUpdate(oldRule, newRule map[string]interface{}) bool {
+func aclRuleNeedsUpdate(pa portAliases, oldRule, newRule map[string]interface{}) bool {
 	// Check basic attributes
 	if oldRule["action"].(string) != newRule["action"].(string) {
 		return true
 	}
 
-	if oldRule["protocol"].(string) != newRule["protocol"].(string) {
+	if !equivalentACLProtocols(oldRule["protocol"].(string), newRule["protocol"].(string)) {
 		return true
 	}
 
@@ -975,7 +1480,7 @@ func aclRuleNeedsUpdate(oldRule, newRule map[string]interface{}) bool {
 	}
 
 	// Check protocol-specific attributes
-	protocol := newRule["protocol"].(string)
+	protocol, _ := aclProtocol(newRule["protocol"].(string))
 	switch protocol {
 	case "icmp":
 		if oldRule["icmp_type"].(int) != newRule["icmp_type"].(int) {
@@ -987,7 +1492,7 @@ func aclRuleNeedsUpdate(oldRule, newRule map[string]interface{}) bool {
 	case "tcp", "udp":
 		oldPort, _ := oldRule["port"].(string)
 		newPort, _ := newRule["port"].(string)
-		if oldPort != newPort {
+		if !pa.equivalentPorts(oldPort, newPort) {
 			return true
 		}
 	}
@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
@@ -610,6 +611,101 @@ resource "cloudstack_network_acl_ruleset" "managed" {
   }
 }`
 
+func TestAccCloudStackNetworkACLRuleset_report(t *testing.T) {
+	var aclID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackNetworkACLRulesetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackNetworkACLRuleset_report,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkACLRulesetExists("cloudstack_network_acl_ruleset.report"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.report", "managed", "report"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.report", "rule.#", "2"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.report", "unmanaged_rules.#", "0"),
+					func(s *terraform.State) error {
+						rs, ok := s.RootModule().Resources["cloudstack_network_acl_ruleset.report"]
+						if !ok {
+							return fmt.Errorf("Not found: cloudstack_network_acl_ruleset.report")
+						}
+						aclID = rs.Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: func() {
+					testAccCreateOutOfBandACLRule(t, aclID)
+				},
+				Config: testAccCloudStackNetworkACLRuleset_report,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkACLRulesetExists("cloudstack_network_acl_ruleset.report"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.report", "rule.#", "2"),
+					// With managed=report, the out-of-band rule should be reported
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.report", "unmanaged_rules.#", "1"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.report", "unmanaged_rules.0.rule_number", "30"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.report", "unmanaged_rules.0.port", "443"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.report", "unmanaged_rules.0.cidr_list.0", "10.0.0.0/8"),
+					// But it should not be deleted
+					func(s *terraform.State) error {
+						return testAccCheckOutOfBandACLRuleExists(aclID)
+					},
+				),
+			},
+		},
+	})
+}
+
+const testAccCloudStackNetworkACLRuleset_report = `
+resource "cloudstack_vpc" "report" {
+  name = "terraform-vpc-report"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_network_acl" "report" {
+  name = "terraform-acl-report"
+  description = "terraform-acl-report-text"
+  vpc_id = cloudstack_vpc.report.id
+}
+
+resource "cloudstack_network_acl_ruleset" "report" {
+  acl_id = cloudstack_network_acl.report.id
+  managed = "report"
+
+  rule {
+    rule_number  = 10
+    action       = "allow"
+    cidr_list    = ["172.18.100.0/24"]
+    protocol     = "tcp"
+    port         = "22"
+    traffic_type = "ingress"
+    description  = "Allow SSH"
+  }
+
+  rule {
+    rule_number  = 20
+    action       = "allow"
+    cidr_list    = ["172.18.100.0/24"]
+    protocol     = "tcp"
+    port         = "80"
+    traffic_type = "ingress"
+    description  = "Allow HTTP"
+  }
+}`
+
 func TestAccCloudStackNetworkACLRuleset_insert(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -810,6 +906,149 @@ resource "cloudstack_network_acl_ruleset" "baz" {
   }
 }`
 
+func TestAccCloudStackNetworkACLRuleset_renumber(t *testing.T) {
+	uuids := make(map[string]string)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackNetworkACLRulesetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackNetworkACLRuleset_renumber_initial,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkACLRulesetExists("cloudstack_network_acl_ruleset.renumber"),
+					testAccCheckCloudStackNetworkACLRulesetUUIDs(
+						"cloudstack_network_acl_ruleset.renumber", uuids, false),
+				),
+			},
+
+			{
+				Config: testAccCloudStackNetworkACLRuleset_renumber_shifted,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkACLRulesetExists("cloudstack_network_acl_ruleset.renumber"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.renumber", "rule.#", "4"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_network_acl_ruleset.renumber", "rule.*", map[string]string{
+							"rule_number": "20",
+							"port":        "80",
+							"description": "Allow HTTP",
+						}),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_network_acl_ruleset.renumber", "rule.*", map[string]string{
+							"rule_number": "30",
+							"port":        "443",
+							"description": "Allow HTTPS",
+						}),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_network_acl_ruleset.renumber", "rule.*", map[string]string{
+							"rule_number": "40",
+							"port":        "3306",
+							"description": "Allow MySQL",
+						}),
+					// The moved rules are renumbered, not recreated
+					testAccCheckCloudStackNetworkACLRulesetUUIDs(
+						"cloudstack_network_acl_ruleset.renumber", uuids, true),
+				),
+			},
+
+			{
+				Config: testAccCloudStackNetworkACLRuleset_renumber_swapped,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkACLRulesetExists("cloudstack_network_acl_ruleset.renumber"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.renumber", "rule.#", "4"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_network_acl_ruleset.renumber", "rule.*", map[string]string{
+							"rule_number": "30",
+							"port":        "3306",
+							"description": "Allow MySQL",
+						}),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_network_acl_ruleset.renumber", "rule.*", map[string]string{
+							"rule_number": "40",
+							"port":        "443",
+							"description": "Allow HTTPS",
+						}),
+					testAccCheckCloudStackNetworkACLRulesetUUIDs(
+						"cloudstack_network_acl_ruleset.renumber", uuids, true),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckCloudStackNetworkACLRulesetUUIDs records the UUID of each rule
+// by its description, or verifies the recorded UUIDs did not change.
+func testAccCheckCloudStackNetworkACLRulesetUUIDs(n string, uuids map[string]string, verify bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		for k, v := range rs.Primary.Attributes {
+			if !strings.HasPrefix(k, "rule.") || !strings.HasSuffix(k, ".description") {
+				continue
+			}
+
+			uuid := rs.Primary.Attributes[strings.TrimSuffix(k, "description")+"uuid"]
+			if !verify {
+				uuids[v] = uuid
+				continue
+			}
+
+			if old, ok := uuids[v]; ok && old != uuid {
+				return fmt.Errorf("Rule %q was recreated: %s != %s", v, old, uuid)
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestAccCloudStackNetworkACLRuleset_csv(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackNetworkACLRulesetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackNetworkACLRuleset_csv,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkACLRulesetExists("cloudstack_network_acl_ruleset.csv"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network_acl_ruleset.csv", "rule.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_network_acl_ruleset.csv", "rule.*", map[string]string{
+							"rule_number":  "10",
+							"action":       "allow",
+							"protocol":     "tcp",
+							"port":         "22",
+							"traffic_type": "ingress",
+							"description":  "Allow SSH",
+						}),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_network_acl_ruleset.csv", "rule.*", map[string]string{
+							"rule_number":  "20",
+							"action":       "allow",
+							"protocol":     "icmp",
+							"icmp_type":    "8",
+							"icmp_code":    "0",
+							"traffic_type": "ingress",
+						}),
+					resource.TestCheckResourceAttr(
+						"data.cloudstack_network_acl_rules.csv", "rules_csv",
+						"number,action,cidrlist,protocol,startport,endport,icmptype,icmpcode,traffictype,reason\n"+
+							"10,allow,172.18.100.0/24,tcp,22,22,,,ingress,Allow SSH\n"+
+							"20,allow,172.18.100.0/24,icmp,,,8,0,ingress,Allow ping\n"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackNetworkACLRuleset_not_managed(t *testing.T) {
 	var aclID string
 
@@ -2223,3 +2462,386 @@ resource "cloudstack_network_acl_ruleset" "icmp_test" {
   }
 }
 `
+
+const testAccCloudStackNetworkACLRuleset_renumber_initial = `
+resource "cloudstack_vpc" "renumber" {
+  name = "terraform-vpc-ruleset-renumber"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_network_acl" "renumber" {
+  name = "terraform-acl-ruleset-renumber"
+  description = "terraform-acl-ruleset-renumber-text"
+  vpc_id = cloudstack_vpc.renumber.id
+}
+
+resource "cloudstack_network_acl_ruleset" "renumber" {
+  acl_id = cloudstack_network_acl.renumber.id
+
+  rule {
+    rule_number = 10
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "22"
+    traffic_type = "ingress"
+    description = "Allow SSH"
+  }
+
+  rule {
+    rule_number = 20
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "443"
+    traffic_type = "ingress"
+    description = "Allow HTTPS"
+  }
+
+  rule {
+    rule_number = 30
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "3306"
+    traffic_type = "ingress"
+    description = "Allow MySQL"
+  }
+}`
+
+const testAccCloudStackNetworkACLRuleset_renumber_shifted = `
+resource "cloudstack_vpc" "renumber" {
+  name = "terraform-vpc-ruleset-renumber"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_network_acl" "renumber" {
+  name = "terraform-acl-ruleset-renumber"
+  description = "terraform-acl-ruleset-renumber-text"
+  vpc_id = cloudstack_vpc.renumber.id
+}
+
+resource "cloudstack_network_acl_ruleset" "renumber" {
+  acl_id = cloudstack_network_acl.renumber.id
+
+  rule {
+    rule_number = 10
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "22"
+    traffic_type = "ingress"
+    description = "Allow SSH"
+  }
+
+  rule {
+    rule_number = 20
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "80"
+    traffic_type = "ingress"
+    description = "Allow HTTP"
+  }
+
+  rule {
+    rule_number = 30
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "443"
+    traffic_type = "ingress"
+    description = "Allow HTTPS"
+  }
+
+  rule {
+    rule_number = 40
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "3306"
+    traffic_type = "ingress"
+    description = "Allow MySQL"
+  }
+}`
+
+const testAccCloudStackNetworkACLRuleset_renumber_swapped = `
+resource "cloudstack_vpc" "renumber" {
+  name = "terraform-vpc-ruleset-renumber"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_network_acl" "renumber" {
+  name = "terraform-acl-ruleset-renumber"
+  description = "terraform-acl-ruleset-renumber-text"
+  vpc_id = cloudstack_vpc.renumber.id
+}
+
+resource "cloudstack_network_acl_ruleset" "renumber" {
+  acl_id = cloudstack_network_acl.renumber.id
+
+  rule {
+    rule_number = 10
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "22"
+    traffic_type = "ingress"
+    description = "Allow SSH"
+  }
+
+  rule {
+    rule_number = 20
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "80"
+    traffic_type = "ingress"
+    description = "Allow HTTP"
+  }
+
+  rule {
+    rule_number = 30
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "3306"
+    traffic_type = "ingress"
+    description = "Allow MySQL"
+  }
+
+  rule {
+    rule_number = 40
+    action = "allow"
+    cidr_list = ["172.18.100.0/24"]
+    protocol = "tcp"
+    port = "443"
+    traffic_type = "ingress"
+    description = "Allow HTTPS"
+  }
+}`
+
+const testAccCloudStackNetworkACLRuleset_csv = `
+resource "cloudstack_vpc" "csv" {
+  name = "terraform-vpc-ruleset-csv"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_network_acl" "csv" {
+  name = "terraform-acl-ruleset-csv"
+  description = "terraform-acl-ruleset-csv-text"
+  vpc_id = cloudstack_vpc.csv.id
+}
+
+resource "cloudstack_network_acl_ruleset" "csv" {
+  acl_id = cloudstack_network_acl.csv.id
+  rules_csv = <<-EOT
+    number,action,cidrlist,protocol,startport,endport,icmptype,icmpcode,traffictype,reason
+    10,allow,172.18.100.0/24,tcp,22,22,,,ingress,Allow SSH
+    20,allow,172.18.100.0/24,icmp,,,8,0,ingress,Allow ping
+  EOT
+}
+
+data "cloudstack_network_acl_rules" "csv" {
+  acl_id = cloudstack_network_acl_ruleset.csv.id
+}`
+
+func TestPlanACLRuleChanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		old       []map[string]interface{}
+		new       []map[string]interface{}
+		updates   []string
+		renumbers []string
+		creates   []int
+		deletes   []string
+		numbers   map[string]int
+	}{
+		{
+			name: "insert in the middle",
+			old: []map[string]interface{}{
+				testACLRule(1, "a", "tcp", "22"),
+				testACLRule(2, "b", "tcp", "80"),
+				testACLRule(3, "c", "tcp", "443"),
+			},
+			new: []map[string]interface{}{
+				testACLRule(1, "", "tcp", "22"),
+				testACLRule(2, "", "udp", "53"),
+				testACLRule(3, "", "tcp", "80"),
+				testACLRule(4, "", "tcp", "443"),
+			},
+			renumbers: []string{"b:2->3", "c:3->4"},
+			creates:   []int{2},
+			numbers:   map[string]int{"a": 1, "b": 3, "c": 4},
+		},
+		{
+			name: "shift every rule up by one",
+			old: []map[string]interface{}{
+				testACLRule(1, "a", "tcp", "22"),
+				testACLRule(2, "b", "tcp", "80"),
+				testACLRule(3, "c", "tcp", "443"),
+			},
+			new: []map[string]interface{}{
+				testACLRule(2, "", "tcp", "22"),
+				testACLRule(3, "", "tcp", "80"),
+				testACLRule(4, "", "tcp", "443"),
+			},
+			renumbers: []string{"a:1->2", "b:2->3", "c:3->4"},
+			numbers:   map[string]int{"a": 2, "b": 3, "c": 4},
+		},
+		{
+			name: "swap two rule numbers",
+			old: []map[string]interface{}{
+				testACLRule(1, "a", "tcp", "22"),
+				testACLRule(2, "b", "tcp", "80"),
+				testACLRule(3, "c", "tcp", "443"),
+			},
+			new: []map[string]interface{}{
+				testACLRule(1, "", "tcp", "80"),
+				testACLRule(2, "", "tcp", "22"),
+				testACLRule(3, "", "tcp", "443"),
+			},
+			renumbers: []string{"b:2->1", "a:1->2"},
+			numbers:   map[string]int{"a": 2, "b": 1, "c": 3},
+		},
+		{
+			name: "port change",
+			old: []map[string]interface{}{
+				testACLRule(1, "a", "tcp", "22"),
+			},
+			new: []map[string]interface{}{
+				testACLRule(1, "", "tcp", "2222"),
+			},
+			updates: []string{"a:1"},
+			numbers: map[string]int{"a": 1},
+		},
+		{
+			name: "equivalent protocol",
+			old: []map[string]interface{}{
+				testACLRule(1, "a", "tcp", "22"),
+			},
+			new: []map[string]interface{}{
+				testACLRule(1, "", "6", "22"),
+			},
+			numbers: map[string]int{"a": 1},
+		},
+		{
+			name: "protocol change",
+			old: []map[string]interface{}{
+				testACLRule(1, "a", "tcp", "22"),
+				testACLRule(2, "b", "tcp", "80"),
+			},
+			new: []map[string]interface{}{
+				testACLRule(1, "", "udp", "22"),
+				testACLRule(2, "", "tcp", "80"),
+			},
+			creates: []int{1},
+			deletes: []string{"a"},
+			numbers: map[string]int{"b": 2},
+		},
+		{
+			name: "delete and create the same number",
+			old: []map[string]interface{}{
+				testACLRule(10, "a", "tcp", "22"),
+				testACLRule(20, "b", "udp", "53"),
+				testACLRule(30, "c", "tcp", "80"),
+			},
+			new: []map[string]interface{}{
+				testACLRule(10, "", "tcp", "22"),
+				testACLRule(20, "", "icmp", ""),
+			},
+			creates: []int{20},
+			deletes: []string{"b", "c"},
+			numbers: map[string]int{"a": 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planACLRuleChanges(nil, testACLRuleSet(tt.old), testACLRuleSet(tt.new))
+
+			var updates, renumbers, deletes []string
+			var creates []int
+			for _, pair := range plan.updates {
+				updates = append(updates, fmt.Sprintf("%s:%d",
+					pair.oldRule["uuid"], pair.newRule["rule_number"]))
+			}
+			for _, pair := range plan.renumbers {
+				renumbers = append(renumbers, fmt.Sprintf("%s:%d->%d",
+					pair.oldRule["uuid"], pair.oldRule["rule_number"], pair.newRule["rule_number"]))
+			}
+			for _, rule := range plan.creates {
+				creates = append(creates, rule["rule_number"].(int))
+			}
+			for _, rule := range plan.deletes {
+				deletes = append(deletes, rule["uuid"].(string))
+			}
+
+			if fmt.Sprint(updates) != fmt.Sprint(tt.updates) {
+				t.Errorf("updates = %v, expected %v", updates, tt.updates)
+			}
+			if fmt.Sprint(renumbers) != fmt.Sprint(tt.renumbers) {
+				t.Errorf("renumbers = %v, expected %v", renumbers, tt.renumbers)
+			}
+			if fmt.Sprint(creates) != fmt.Sprint(tt.creates) {
+				t.Errorf("creates = %v, expected %v", creates, tt.creates)
+			}
+			if fmt.Sprint(deletes) != fmt.Sprint(tt.deletes) {
+				t.Errorf("deletes = %v, expected %v", deletes, tt.deletes)
+			}
+			if fmt.Sprint(plan.numbers) != fmt.Sprint(tt.numbers) {
+				t.Errorf("numbers = %v, expected %v", plan.numbers, tt.numbers)
+			}
+		})
+	}
+}
+
+func TestFreeACLRuleNumber(t *testing.T) {
+	number, err := freeACLRuleNumber(map[int]string{})
+	if err != nil || number != 1 {
+		t.Errorf("freeACLRuleNumber of no rules = %d, %v, expected 1", number, err)
+	}
+
+	number, err = freeACLRuleNumber(map[int]string{5: "a", 10: "b", 7: "c"})
+	if err != nil || number != 11 {
+		t.Errorf("freeACLRuleNumber = %d, %v, expected 11", number, err)
+	}
+
+	if _, err := freeACLRuleNumber(map[int]string{65535: "a"}); err == nil {
+		t.Errorf("freeACLRuleNumber: expected an error when rule number 65535 is in use")
+	}
+}
+
+func testACLRule(number int, uuid, protocol, port string) map[string]interface{} {
+	return map[string]interface{}{
+		"rule_number":  number,
+		"action":       "allow",
+		"cidr_list":    schema.NewSet(schema.HashString, []interface{}{"10.0.0.0/8"}),
+		"protocol":     protocol,
+		"icmp_type":    -1,
+		"icmp_code":    -1,
+		"port":         port,
+		"traffic_type": "ingress",
+		"description":  "",
+		"uuid":         uuid,
+	}
+}
+
+func testACLRuleSet(rules []map[string]interface{}) *schema.Set {
+	elem := resourceCloudStackNetworkACLRuleset().Schema["rule"].Elem.(*schema.Resource)
+
+	s := schema.NewSet(schema.HashResource(elem), nil)
+	for _, rule := range rules {
+		s.Add(rule)
+	}
+
+	return s
+}
@@ -59,6 +59,37 @@ func TestAccCloudStackNetwork_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackNetwork_restart(t *testing.T) {
+	var network cloudstack.Network
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackNetworkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackNetwork_restart("1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkExists(
+						"cloudstack_network.foo", &network),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network.foo", "restart_trigger", "1"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackNetwork_restart("2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackNetworkExists(
+						"cloudstack_network.foo", &network),
+					resource.TestCheckResourceAttr(
+						"cloudstack_network.foo", "restart_trigger", "2"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackNetwork_project(t *testing.T) {
 	var network cloudstack.Network
 
@@ -437,6 +468,19 @@ resource "cloudstack_network" "foo" {
   }
 }`
 
+func testAccCloudStackNetwork_restart(trigger string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+  restart_trigger = "%s"
+  restart_cleanup = true
+}`, trigger)
+}
+
 const testAccCloudStackNetwork_project = `
 resource "cloudstack_network" "foo" {
   name = "terraform-network"
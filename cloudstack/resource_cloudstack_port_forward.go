@@ -20,12 +20,12 @@
 package cloudstack
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/go-multierror"
@@ -41,6 +41,7 @@ func resourceCloudStackPortForward() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceCloudStackPortForwardImport,
 		},
+		CustomizeDiff: resourceCloudStackPortForwardCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"ip_address_id": {
@@ -104,6 +105,30 @@ func resourceCloudStackPortForward() *schema.Resource {
 							Optional: true,
 						},
 
+						"cidr_list": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"tags": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+
+						"for_display": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+
 						"uuid": {
 							Type:     schema.TypeString,
 							Computed: true,
@@ -152,32 +177,31 @@ func resourceCloudStackPortForwardCreate(d *schema.ResourceData, meta interface{
 
 func createPortForwards(d *schema.ResourceData, meta interface{}, forwards *schema.Set, nrs *schema.Set) error {
 	var errs *multierror.Error
+	var mu sync.Mutex
 
 	var wg sync.WaitGroup
 	wg.Add(nrs.Len())
 
-	sem := make(chan struct{}, 10)
+	sem := make(jobSlots, 10)
 	for _, forward := range nrs.List() {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(forward map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Create a single forward
-			err := createPortForward(d, meta, forward)
+			err := createPortForward(d, meta, sem, forward)
 
 			// If we have a UUID, we need to save the forward
 			if forward["uuid"].(string) != "" {
+				mu.Lock()
 				forwards.Add(forward)
+				mu.Unlock()
 			}
 
 			if err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(forward.(map[string]interface{}))
 	}
 
@@ -186,7 +210,7 @@ func createPortForwards(d *schema.ResourceData, meta interface{}, forwards *sche
 	return errs.ErrorOrNil()
 }
 
-func createPortForward(d *schema.ResourceData, meta interface{}, forward map[string]interface{}) error {
+func createPortForward(d *schema.ResourceData, meta interface{}, sem jobSlots, forward map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 
 	// Make sure all required parameters are there
@@ -235,19 +259,207 @@ func createPortForward(d *schema.ResourceData, meta interface{}, forward map[str
 		p.SetNetworkid(vm.Nic[0].Networkid)
 	}
 
+	if cidrs := setToStrings(forward["cidr_list"]); len(cidrs) > 0 {
+		p.SetCidrlist(cidrs)
+	}
+
+	if forDisplay, ok := forward["for_display"].(bool); ok {
+		p.SetFordisplay(forDisplay)
+	}
+
 	// Do not open the firewall automatically in any case
 	p.SetOpenfirewall(false)
 
-	r, err := cs.Firewall.CreatePortForwardingRule(p)
+	sem.take()
+	r, err := jobs(cs).client().Firewall.CreatePortForwardingRule(p)
+	sem.release()
+	if err == nil {
+		err = jobs(cs).wait(r.JobID)
+	}
 	if err != nil {
 		return err
 	}
 
 	forward["uuid"] = r.Id
 
+	if tags := ruleTags(forward); len(tags) > 0 {
+		tp := cs.Resourcetags.NewCreateTagsParams([]string{r.Id}, "PortForwardingRule", tags)
+		if _, err := cs.Resourcetags.CreateTags(tp); err != nil {
+			return fmt.Errorf("Error setting tags on port forward %s: %s", r.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// portForwardKey returns the protocol and public ports of a forward, which
+// identify the public port binding of the forward.
+func portForwardKey(forward map[string]interface{}) string {
+	return fmt.Sprintf("%s/%s",
+		strings.ToLower(forward["protocol"].(string)),
+		formatPortRange(forward["public_port"].(int), forward["public_end_port"].(int)))
+}
+
+// portForwardNeedsReplace reports whether a forward using the same public
+// port binding has to be replaced, as its CIDR list cannot be updated.
+func portForwardNeedsReplace(o, n map[string]interface{}) bool {
+	return strings.Join(setToStrings(o["cidr_list"]), ",") != strings.Join(setToStrings(n["cidr_list"]), ",")
+}
+
+// portForwardNeedsUpdate reports whether any of the values that can be
+// updated in place have changed.
+func portForwardNeedsUpdate(o, n map[string]interface{}) bool {
+	for _, k := range []string{"private_port", "private_end_port", "virtual_machine_id", "vm_guest_ip", "for_display"} {
+		if o[k] != n[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// updatePortForwards updates the forwards that use the same public port
+// binding in place, and replaces them when their CIDR list has changed. The
+// processed forwards are removed from ors and nrs.
+func updatePortForwards(d *schema.ResourceData, meta interface{}, forwards *schema.Set, ors *schema.Set, nrs *schema.Set) error {
+	var errs *multierror.Error
+	var mu sync.Mutex
+
+	// Pair the old and new forwards that use the same public port binding
+	olds := make(map[string]map[string]interface{}, ors.Len())
+	for _, o := range ors.List() {
+		olds[portForwardKey(o.(map[string]interface{}))] = o.(map[string]interface{})
+	}
+
+	type pair struct {
+		o, n map[string]interface{}
+	}
+
+	var replace []pair
+	for _, n := range nrs.List() {
+		nforward := n.(map[string]interface{})
+
+		oforward, ok := olds[portForwardKey(nforward)]
+		if !ok || oforward["uuid"].(string) == "" {
+			continue
+		}
+
+		ors.Remove(oforward)
+		nrs.Remove(nforward)
+
+		if portForwardNeedsReplace(oforward, nforward) {
+			replace = append(replace, pair{oforward, nforward})
+			continue
+		}
+
+		if err := updatePortForward(d, meta, oforward, nforward); err != nil {
+			forwards.Add(oforward)
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		forwards.Add(nforward)
+	}
+
+	// Replace each forward right after deleting the old forward, so the
+	// public port is unbound as short as possible
+	var wg sync.WaitGroup
+	wg.Add(len(replace))
+
+	sem := make(jobSlots, 10)
+	for _, p := range replace {
+		go func(p pair) {
+			defer wg.Done()
+
+			if err := deletePortForward(d, meta, sem, p.o); err != nil {
+				mu.Lock()
+				forwards.Add(p.o)
+				errs = multierror.Append(errs, err)
+				mu.Unlock()
+				return
+			}
+
+			err := createPortForward(d, meta, sem, p.n)
+
+			// If we have a UUID, we need to save the forward
+			if p.n["uuid"].(string) != "" {
+				mu.Lock()
+				forwards.Add(p.n)
+				mu.Unlock()
+			}
+
+			if err != nil {
+				mu.Lock()
+				errs = multierror.Append(errs, err)
+				mu.Unlock()
+			}
+		}(p)
+	}
+
+	wg.Wait()
+
+	return errs.ErrorOrNil()
+}
+
+func updatePortForward(d *schema.ResourceData, meta interface{}, o, n map[string]interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+	id := o["uuid"].(string)
+
+	// Make sure all required parameters are there
+	if err := verifyPortForwardParams(d, n); err != nil {
+		return err
+	}
+
+	if portForwardNeedsUpdate(o, n) {
+		p := cs.Firewall.NewUpdatePortForwardingRuleParams(id)
+
+		privatePort := n["private_port"].(int)
+		p.SetPrivateport(privatePort)
+		if privateEndPort := n["private_end_port"].(int); privateEndPort != 0 {
+			p.SetPrivateendport(privateEndPort)
+		} else {
+			p.SetPrivateendport(privatePort)
+		}
+
+		p.SetVirtualmachineid(n["virtual_machine_id"].(string))
+		if vmGuestIP := n["vm_guest_ip"].(string); vmGuestIP != "" {
+			p.SetVmguestip(vmGuestIP)
+		}
+
+		p.SetFordisplay(n["for_display"].(bool))
+
+		if _, err := cs.Firewall.UpdatePortForwardingRule(p); err != nil {
+			return fmt.Errorf("Error updating port forward %s: %s", id, err)
+		}
+	}
+
+	if err := updateRuleTags(cs, []string{id}, "PortForwardingRule", o, n); err != nil {
+		return fmt.Errorf("Error updating tags of port forward %s: %s", id, err)
+	}
+
+	n["uuid"] = id
+
 	return nil
 }
 
+func resourceCloudStackPortForwardCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// Reject forwards of the same public ports before any of them are created.
+	// The ports are numbers, so service names and port aliases don't apply.
+	if !portRulesKnown(d, "forward") {
+		return nil
+	}
+
+	var rules []portRule
+	for _, forward := range d.Get("forward").(*schema.Set).List() {
+		forward := forward.(map[string]interface{})
+		rules = append(rules, portRule{
+			scope: strings.ToLower(forward["protocol"].(string)),
+			port:  formatPortRange(forward["public_port"].(int), forward["public_end_port"].(int)),
+		})
+	}
+
+	return checkPortRules(nil, rules, false)
+}
+
 func resourceCloudStackPortForwardImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	cs := meta.(*cloudstack.CloudStackClient)
 
@@ -308,8 +520,11 @@ func resourceCloudStackPortForwardImport(d *schema.ResourceData, meta interface{
 			"private_port":       privPort,
 			"public_port":        pubPort,
 			"virtual_machine_id": f.Virtualmachineid,
+			"cidr_list":          portForwardCIDRList(f, nil),
+			"for_display":        f.Fordisplay,
 			"uuid":               f.Id,
 		}
+		readRuleTags(forward, f.Tags)
 
 		if f.Privateendport != "" && f.Privateendport != f.Privateport {
 			privEndPort, err := strconv.Atoi(f.Privateendport)
@@ -459,6 +674,10 @@ func resourceCloudStackPortForwardRead(d *schema.ResourceData, meta interface{})
 				forward["vm_guest_ip"] = f.Vmguestip
 			}
 
+			forward["cidr_list"] = portForwardCIDRList(f, forward)
+			forward["for_display"] = f.Fordisplay
+			readRuleTags(forward, f.Tags)
+
 			forwards.Add(forward)
 		}
 	}
@@ -505,7 +724,23 @@ func resourceCloudStackPortForwardUpdate(d *schema.ResourceData, meta interface{
 		// set to make sure we end up in a consistent state
 		forwards := o.(*schema.Set).Intersection(n.(*schema.Set))
 
-		// First loop through all the old forwards and delete them
+		// First update or replace the forwards that keep their public port
+		if ors.Len() > 0 && nrs.Len() > 0 {
+			err := updatePortForwards(d, meta, forwards, ors, nrs)
+			if err != nil {
+				// Keep the forwards that are not processed yet
+				for _, forward := range ors.List() {
+					forwards.Add(forward)
+				}
+
+				// We need to update this first to preserve the correct state
+				d.Set("forward", forwards)
+
+				return err
+			}
+		}
+
+		// Then loop through all the old forwards and delete them
 		if ors.Len() > 0 {
 			err := deletePortForwards(d, meta, forwards, ors)
 
@@ -555,32 +790,31 @@ func resourceCloudStackPortForwardDelete(d *schema.ResourceData, meta interface{
 
 func deletePortForwards(d *schema.ResourceData, meta interface{}, forwards *schema.Set, ors *schema.Set) error {
 	var errs *multierror.Error
+	var mu sync.Mutex
 
 	var wg sync.WaitGroup
 	wg.Add(ors.Len())
 
-	sem := make(chan struct{}, 10)
+	sem := make(jobSlots, 10)
 	for _, forward := range ors.List() {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(forward map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Delete a single forward
-			err := deletePortForward(d, meta, forward)
+			err := deletePortForward(d, meta, sem, forward)
 
 			// If we have a UUID, we need to save the forward
 			if forward["uuid"].(string) != "" {
+				mu.Lock()
 				forwards.Add(forward)
+				mu.Unlock()
 			}
 
 			if err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(forward.(map[string]interface{}))
 	}
 
@@ -589,14 +823,20 @@ func deletePortForwards(d *schema.ResourceData, meta interface{}, forwards *sche
 	return errs.ErrorOrNil()
 }
 
-func deletePortForward(d *schema.ResourceData, meta interface{}, forward map[string]interface{}) error {
+func deletePortForward(d *schema.ResourceData, meta interface{}, sem jobSlots, forward map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 
 	// Create the parameter struct
 	p := cs.Firewall.NewDeletePortForwardingRuleParams(forward["uuid"].(string))
 
 	// Delete the forward
-	if _, err := cs.Firewall.DeletePortForwardingRule(p); err != nil {
+	sem.take()
+	r, err := jobs(cs).client().Firewall.DeletePortForwardingRule(p)
+	sem.release()
+	if err == nil {
+		err = jobs(cs).wait(r.JobID)
+	}
+	if err != nil {
 		// This is a very poor way to be told the ID does no longer exist :(
 		if !strings.Contains(err.Error(), fmt.Sprintf(
 			"Invalid parameter id value=%s due to incorrect long value format, "+
@@ -617,5 +857,32 @@ func verifyPortForwardParams(d *schema.ResourceData, forward map[string]interfac
 		return fmt.Errorf(
 			"%s is not a valid protocol. Valid options are 'tcp' and 'udp'", protocol)
 	}
+
+	if tags, ok := forward["tags"].(map[string]interface{}); ok {
+		if _, ok := tags["description"]; ok {
+			return fmt.Errorf(
+				"The 'description' tag is reserved, use the description parameter instead")
+		}
+	}
+
 	return nil
 }
+
+// portForwardCIDRList returns the CIDR list of a forward. A forward without a
+// CIDR list allows any source, which the API returns as 0.0.0.0/0.
+func portForwardCIDRList(f *cloudstack.PortForwardingRule, forward map[string]interface{}) *schema.Set {
+	cidrs := cidrSetFromList(f.Cidrlist)
+
+	configured := &schema.Set{F: schema.HashString}
+	if forward != nil {
+		if c, ok := forward["cidr_list"].(*schema.Set); ok {
+			configured = c
+		}
+	}
+
+	if cidrs.Len() == 1 && cidrs.Contains("0.0.0.0/0") && configured.Len() == 0 {
+		return &schema.Set{F: schema.HashString}
+	}
+
+	return cidrs
+}
@@ -121,6 +121,42 @@ func TestAccCloudStackPortForward_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackPortForward_updateInPlace(t *testing.T) {
+	var uuid string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackPortForwardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackPortForward_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackPortForwardsExist("cloudstack_port_forward.foo"),
+					testAccCheckCloudStackPortForwardUUID("cloudstack_port_forward.foo", &uuid),
+				),
+			},
+
+			{
+				Config: testAccCloudStackPortForward_updateInPlace,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackPortForwardsExist("cloudstack_port_forward.foo"),
+					testAccCheckCloudStackPortForwardUUID("cloudstack_port_forward.foo", &uuid),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_port_forward.foo", "forward.*", map[string]string{
+							"private_port":   "8443",
+							"public_port":    "8443",
+							"description":    "HTTPS",
+							"tags.%":         "1",
+							"tags.terraform": "true",
+							"for_display":    "false",
+						}),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackPortForward_portRange(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -195,6 +231,36 @@ func testAccCheckCloudStackPortForwardsExist(n string) resource.TestCheckFunc {
 	}
 }
 
+// testAccCheckCloudStackPortForwardUUID stores the UUID of the single forward
+// of a port forward resource, and verifies that it is not changed by updates.
+func testAccCheckCloudStackPortForwardUUID(n string, uuid *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		for k, id := range rs.Primary.Attributes {
+			if !strings.Contains(k, "forward.") || !strings.HasSuffix(k, ".uuid") {
+				continue
+			}
+
+			if *uuid == "" {
+				*uuid = id
+				return nil
+			}
+
+			if id != *uuid {
+				return fmt.Errorf("Port forward was replaced: %s, expected: %s", id, *uuid)
+			}
+
+			return nil
+		}
+
+		return fmt.Errorf("No port forward UUID is set")
+	}
+}
+
 func testAccCheckCloudStackPortForwardAttributes(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -336,6 +402,43 @@ resource "cloudstack_port_forward" "foo" {
   }
 }`
 
+const testAccCloudStackPortForward_updateInPlace = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  display_text = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  source_nat_ip = true
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-updated"
+  service_offering= "Medium Instance"
+  network_id = cloudstack_network.foo.id
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}
+
+resource "cloudstack_port_forward" "foo" {
+  ip_address_id = cloudstack_network.foo.source_nat_ip_id
+
+  forward {
+    protocol = "tcp"
+    private_port = 8443
+    public_port = 8443
+    virtual_machine_id = cloudstack_instance.foobar.id
+    description = "HTTPS"
+    for_display = false
+
+    tags = {
+      terraform = "true"
+    }
+  }
+}`
+
 const testAccCloudStackPortForward_update = `
 resource "cloudstack_network" "foo" {
   name = "terraform-network"
@@ -0,0 +1,196 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackRemoteAccessVPN() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackRemoteAccessVPNCreate,
+		Read:   resourceCloudStackRemoteAccessVPNRead,
+		Update: resourceCloudStackRemoteAccessVPNUpdate,
+		Delete: resourceCloudStackRemoteAccessVPNDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackRemoteAccessVPNImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"ip_address_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"ip_range": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			// Only used when creating the VPN, so changing it has no effect
+			"open_firewall": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"for_display": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"public_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"preshared_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackRemoteAccessVPNCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	ipaddressid := d.Get("ip_address_id").(string)
+
+	// Create a new parameter struct
+	p := cs.VPN.NewCreateRemoteAccessVpnParams(ipaddressid)
+
+	if iprange, ok := d.GetOk("ip_range"); ok {
+		p.SetIprange(iprange.(string))
+	}
+
+	// Only open the firewall when asked for, use a resource if needed
+	p.SetOpenfirewall(d.Get("open_firewall").(bool))
+	p.SetFordisplay(d.Get("for_display").(bool))
+
+	// Create the remote access VPN
+	v, err := cs.VPN.CreateRemoteAccessVpn(p)
+	if err != nil {
+		return fmt.Errorf(
+			"Error creating remote access VPN for IP address ID %s: %s", ipaddressid, err)
+	}
+
+	d.SetId(v.Id)
+
+	return resourceCloudStackRemoteAccessVPNRead(d, meta)
+}
+
+func resourceCloudStackRemoteAccessVPNRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the remote access VPN details
+	v, count, err := cs.VPN.GetRemoteAccessVpnByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Remote access VPN %s does no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("ip_address_id", v.Publicipid)
+	d.Set("ip_range", v.Iprange)
+	d.Set("for_display", v.Fordisplay)
+	d.Set("public_ip", v.Publicip)
+	d.Set("preshared_key", v.Presharedkey)
+	d.Set("state", v.State)
+
+	setValueOrID(d, "project", v.Project, v.Projectid)
+
+	return nil
+}
+
+func resourceCloudStackRemoteAccessVPNUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if d.HasChange("for_display") {
+		// Create a new parameter struct
+		p := cs.VPN.NewUpdateRemoteAccessVpnParams(d.Id())
+		p.SetFordisplay(d.Get("for_display").(bool))
+
+		_, err := cs.VPN.UpdateRemoteAccessVpn(p)
+		if err != nil {
+			return fmt.Errorf("Error updating remote access VPN %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceCloudStackRemoteAccessVPNRead(d, meta)
+}
+
+func resourceCloudStackRemoteAccessVPNDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// The remote access VPN is deleted through its public IP address
+	p := cs.VPN.NewDeleteRemoteAccessVpnParams(d.Get("ip_address_id").(string))
+
+	// Delete the remote access VPN
+	_, err := cs.VPN.DeleteRemoteAccessVpn(p)
+	if err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Get("ip_address_id").(string))) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting remote access VPN %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceCloudStackRemoteAccessVPNImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// open_firewall can't be read back, so assume the default
+	d.Set("open_firewall", false)
+
+	return importStatePassthrough(d, meta)
+}
@@ -0,0 +1,268 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudStackRoutingFirewallRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackRoutingFirewallRuleCreate,
+		Read:   resourceCloudStackRoutingFirewallRuleRead,
+		Update: resourceCloudStackRoutingFirewallRuleUpdate,
+		Delete: resourceCloudStackRoutingFirewallRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"protocol": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"tcp", "udp", "icmp", "all",
+				}, true),
+			},
+
+			"traffic_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ingress",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ingress", "egress"}, false),
+			},
+
+			"cidr_list": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"dest_cidr_list": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"start_port": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"end_port": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"icmp_type": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"icmp_code": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"for_display": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackRoutingFirewallRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	protocol := strings.ToLower(d.Get("protocol").(string))
+
+	// Make sure all required parameters are there
+	if err := verifyRoutingFirewallRuleParams(d); err != nil {
+		return err
+	}
+
+	// Create a new parameter struct
+	p := cs.Network.NewCreateRoutingFirewallRuleParams(d.Get("network_id").(string), protocol)
+	p.SetTraffictype(d.Get("traffic_type").(string))
+	p.SetFordisplay(d.Get("for_display").(bool))
+
+	if cidrs, ok := d.GetOk("cidr_list"); ok {
+		p.SetCidrlist(setToStringList(cidrs.(*schema.Set)))
+	}
+
+	if cidrs, ok := d.GetOk("dest_cidr_list"); ok {
+		p.SetDestcidrlist(setToStringList(cidrs.(*schema.Set)))
+	}
+
+	switch protocol {
+	case "icmp":
+		p.SetIcmptype(d.Get("icmp_type").(int))
+		p.SetIcmpcode(d.Get("icmp_code").(int))
+	case "tcp", "udp":
+		startPort := d.Get("start_port").(int)
+		p.SetStartport(startPort)
+
+		if endPort, ok := d.GetOk("end_port"); ok {
+			p.SetEndport(endPort.(int))
+		} else {
+			p.SetEndport(startPort)
+		}
+	}
+
+	r, err := cs.Network.CreateRoutingFirewallRule(p)
+	if err != nil {
+		return fmt.Errorf("Error creating routing firewall rule: %s", err)
+	}
+
+	d.SetId(r.Id)
+
+	return resourceCloudStackRoutingFirewallRuleRead(d, meta)
+}
+
+func resourceCloudStackRoutingFirewallRuleRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the routing firewall rule details
+	r, count, err := cs.Network.GetRoutingFirewallRuleByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] Routing firewall rule %s does no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("network_id", r.Networkid)
+	d.Set("protocol", r.Protocol)
+	d.Set("traffic_type", strings.ToLower(r.Traffictype))
+	d.Set("cidr_list", cidrSetFromList(r.Cidrlist))
+	d.Set("dest_cidr_list", cidrSetFromList(r.Destcidrlist))
+	d.Set("for_display", r.Fordisplay)
+
+	if strings.ToLower(r.Protocol) == "icmp" {
+		d.Set("icmp_type", r.Icmptype)
+		d.Set("icmp_code", r.Icmpcode)
+	} else {
+		d.Set("start_port", r.Startport)
+		d.Set("end_port", r.Endport)
+	}
+
+	setValueOrID(d, "project", r.Project, r.Projectid)
+
+	return nil
+}
+
+func resourceCloudStackRoutingFirewallRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	if d.HasChange("for_display") {
+		// Create a new parameter struct
+		p := cs.Network.NewUpdateRoutingFirewallRuleParams(d.Id())
+		p.SetFordisplay(d.Get("for_display").(bool))
+
+		_, err := cs.Network.UpdateRoutingFirewallRule(p)
+		if err != nil {
+			return fmt.Errorf("Error updating routing firewall rule %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceCloudStackRoutingFirewallRuleRead(d, meta)
+}
+
+func resourceCloudStackRoutingFirewallRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.Network.NewDeleteRoutingFirewallRuleParams(d.Id())
+
+	if _, err := cs.Network.DeleteRoutingFirewallRule(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting routing firewall rule %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func verifyRoutingFirewallRuleParams(d *schema.ResourceData) error {
+	protocol := strings.ToLower(d.Get("protocol").(string))
+	_, startPort := d.GetOk("start_port")
+
+	switch protocol {
+	case "tcp", "udp":
+		if !startPort {
+			return fmt.Errorf(
+				"Parameter start_port is a required parameter when using protocol %q", protocol)
+		}
+	default:
+		if startPort {
+			return fmt.Errorf(
+				"Parameter start_port can only be used with protocol 'tcp' or 'udp'")
+		}
+	}
+
+	return nil
+}
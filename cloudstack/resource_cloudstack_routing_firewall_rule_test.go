@@ -0,0 +1,240 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackRoutingFirewallRule_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackRoutingFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackRoutingFirewallRule_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackRoutingFirewallRuleExists("cloudstack_routing_firewall_rule.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_routing_firewall_rule.foo", "protocol", "tcp"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_routing_firewall_rule.foo", "start_port", "443"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_routing_firewall_rule.foo", "end_port", "443"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_routing_firewall_rule.foo", "for_display", "true"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackRoutingFirewallRule_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackRoutingFirewallRuleExists("cloudstack_routing_firewall_rule.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_routing_firewall_rule.foo", "for_display", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackRoutingFirewallRule_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackRoutingFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackRoutingFirewallRule_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_routing_firewall_rule.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackRoutingFirewallRuleExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No routing firewall rule ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		rule, _, err := cs.Network.GetRoutingFirewallRuleByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if rule.Id != rs.Primary.ID {
+			return fmt.Errorf("Routing firewall rule not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackRoutingFirewallRuleDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_routing_firewall_rule" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No routing firewall rule ID is set")
+		}
+
+		_, _, err := cs.Network.GetRoutingFirewallRuleByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Routing firewall rule %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackRoutingFirewallRule_basic = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_ipv4_subnet" "foo" {
+  subnet  = "172.30.0.0/20"
+  zone_id = data.cloudstack_zone.zone.id
+}
+
+resource "cloudstack_as_number_range" "foo" {
+  zone_id         = data.cloudstack_zone.zone.id
+  start_as_number = 64600
+  end_as_number   = 64700
+}
+
+resource "cloudstack_network_offering" "routed" {
+  name               = "terraform-routed-offering"
+  display_text       = "terraform-routed-offering"
+  guest_ip_type      = "Isolated"
+  traffic_type       = "Guest"
+  network_mode       = "ROUTED"
+  routing_mode       = "Dynamic"
+  specify_as_number  = true
+  enable             = true
+  supported_services = ["Dhcp", "Dns", "Firewall", "UserData"]
+  service_provider_list = {
+    Dhcp     = "VirtualRouter"
+    Dns      = "VirtualRouter"
+    Firewall = "VirtualRouter"
+    UserData = "VirtualRouter"
+  }
+}
+
+resource "cloudstack_network" "foo" {
+  name             = "terraform-routed-network"
+  cidr             = "172.30.1.0/24"
+  network_offering = cloudstack_network_offering.routed.id
+  zone             = data.cloudstack_zone.zone.name
+
+  depends_on = [
+    cloudstack_ipv4_subnet.foo,
+    cloudstack_as_number_range.foo,
+  ]
+}
+
+resource "cloudstack_routing_firewall_rule" "foo" {
+  network_id = cloudstack_network.foo.id
+  protocol   = "tcp"
+  cidr_list  = ["0.0.0.0/0"]
+  start_port = 443
+}`
+
+const testAccCloudStackRoutingFirewallRule_update = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_ipv4_subnet" "foo" {
+  subnet  = "172.30.0.0/20"
+  zone_id = data.cloudstack_zone.zone.id
+}
+
+resource "cloudstack_as_number_range" "foo" {
+  zone_id         = data.cloudstack_zone.zone.id
+  start_as_number = 64600
+  end_as_number   = 64700
+}
+
+resource "cloudstack_network_offering" "routed" {
+  name               = "terraform-routed-offering"
+  display_text       = "terraform-routed-offering"
+  guest_ip_type      = "Isolated"
+  traffic_type       = "Guest"
+  network_mode       = "ROUTED"
+  routing_mode       = "Dynamic"
+  specify_as_number  = true
+  enable             = true
+  supported_services = ["Dhcp", "Dns", "Firewall", "UserData"]
+  service_provider_list = {
+    Dhcp     = "VirtualRouter"
+    Dns      = "VirtualRouter"
+    Firewall = "VirtualRouter"
+    UserData = "VirtualRouter"
+  }
+}
+
+resource "cloudstack_network" "foo" {
+  name             = "terraform-routed-network"
+  cidr             = "172.30.1.0/24"
+  network_offering = cloudstack_network_offering.routed.id
+  zone             = data.cloudstack_zone.zone.name
+
+  depends_on = [
+    cloudstack_ipv4_subnet.foo,
+    cloudstack_as_number_range.foo,
+  ]
+}
+
+resource "cloudstack_routing_firewall_rule" "foo" {
+  network_id  = cloudstack_network.foo.id
+  protocol    = "tcp"
+  cidr_list   = ["0.0.0.0/0"]
+  start_port  = 443
+  for_display = false
+}`
@@ -20,12 +20,13 @@
 package cloudstack
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	multierror "github.com/hashicorp/go-multierror"
@@ -49,6 +50,10 @@ func resourceCloudStackSecurityGroupRule() *schema.Resource {
 		Read:   resourceCloudStackSecurityGroupRuleRead,
 		Update: resourceCloudStackSecurityGroupRuleUpdate,
 		Delete: resourceCloudStackSecurityGroupRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackSecurityGroupRuleImport,
+		},
+		CustomizeDiff: resourceCloudStackSecurityGroupRuleCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"security_group_id": {
@@ -106,6 +111,39 @@ func resourceCloudStackSecurityGroupRule() *schema.Resource {
 							Set:      schema.HashString,
 						},
 
+						"source_security_group": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+
+									"id": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+
+									"account": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+
+									"domain": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+
+									"project": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+
 						"uuids": {
 							Type:     schema.TypeMap,
 							Computed: true,
@@ -152,95 +190,108 @@ func resourceCloudStackSecurityGroupRuleCreate(d *schema.ResourceData, meta inte
 }
 
 func createSecurityGroupRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, nrs *schema.Set) error {
+	return applyFirewallRules(d, rules, nrs, "creating", func(sem jobSlots, rule map[string]interface{}) error {
+		return createSecurityGroupRuleSources(d, meta, sem, rule)
+	})
+}
+
+// createSecurityGroupRuleSources creates the rule for every CIDR and security
+// group the rule applies to.
+func createSecurityGroupRuleSources(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 	var errs *multierror.Error
 
-	var wg sync.WaitGroup
-	wg.Add(nrs.Len())
-
-	sem := make(chan struct{}, d.Get("parallelism").(int))
-	for _, rule := range nrs.List() {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
+	// Make sure all required parameters are there
+	if err := verifySecurityGroupRuleParams(d, portAliasesOf(cs), rule); err != nil {
+		return err
+	}
 
-		go func(rule map[string]interface{}) {
-			defer wg.Done()
-			sem <- struct{}{}
+	var p authorizeSecurityGroupParams
 
-			// Make sure all required parameters are there
-			if err := verifySecurityGroupRuleParams(d, rule); err != nil {
-				errs = multierror.Append(errs, err)
-				return
+	if cidrList, ok := rule["cidr_list"].(*schema.Set); ok && cidrList.Len() > 0 {
+		for _, cidr := range cidrList.List() {
+			// Create a new parameter struct
+			switch rule["traffic_type"].(string) {
+			case "ingress":
+				p = cs.SecurityGroup.NewAuthorizeSecurityGroupIngressParams()
+			case "egress":
+				p = cs.SecurityGroup.NewAuthorizeSecurityGroupEgressParams()
 			}
 
-			var p authorizeSecurityGroupParams
+			p.SetSecuritygroupid(d.Id())
+			p.SetCidrlist([]string{cidr.(string)})
 
-			if cidrList, ok := rule["cidr_list"].(*schema.Set); ok && cidrList.Len() > 0 {
-				for _, cidr := range cidrList.List() {
-					// Create a new parameter struct
-					switch rule["traffic_type"].(string) {
-					case "ingress":
-						p = cs.SecurityGroup.NewAuthorizeSecurityGroupIngressParams()
-					case "egress":
-						p = cs.SecurityGroup.NewAuthorizeSecurityGroupEgressParams()
-					}
+			// Create a single rule
+			err := createSecurityGroupRule(d, meta, sem, rule, p, cidr.(string))
+			if err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+	}
 
-					p.SetSecuritygroupid(d.Id())
-					p.SetCidrlist([]string{cidr.(string)})
+	if usgList, ok := rule["user_security_group_list"].(*schema.Set); ok && usgList.Len() > 0 {
+		for _, usg := range usgList.List() {
+			sg, _, err := cs.SecurityGroup.GetSecurityGroupByName(
+				usg.(string),
+				cloudstack.WithProject(d.Get("project").(string)),
+			)
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
 
-					// Create a single rule
-					err := createSecurityGroupRule(d, meta, rule, p, cidr.(string))
-					if err != nil {
-						errs = multierror.Append(errs, err)
-					}
-				}
+			// Create a new parameter struct
+			switch rule["traffic_type"].(string) {
+			case "ingress":
+				p = cs.SecurityGroup.NewAuthorizeSecurityGroupIngressParams()
+			case "egress":
+				p = cs.SecurityGroup.NewAuthorizeSecurityGroupEgressParams()
 			}
 
-			if usgList, ok := rule["user_security_group_list"].(*schema.Set); ok && usgList.Len() > 0 {
-				for _, usg := range usgList.List() {
-					sg, _, err := cs.SecurityGroup.GetSecurityGroupByName(
-						usg.(string),
-						cloudstack.WithProject(d.Get("project").(string)),
-					)
-					if err != nil {
-						errs = multierror.Append(errs, err)
-						continue
-					}
+			p.SetSecuritygroupid(d.Id())
+			p.SetUsersecuritygrouplist(map[string]string{sg.Account: usg.(string)})
 
-					// Create a new parameter struct
-					switch rule["traffic_type"].(string) {
-					case "ingress":
-						p = cs.SecurityGroup.NewAuthorizeSecurityGroupIngressParams()
-					case "egress":
-						p = cs.SecurityGroup.NewAuthorizeSecurityGroupEgressParams()
-					}
+			// Create a single rule
+			err = createSecurityGroupRule(d, meta, sem, rule, p, usg.(string))
+			if err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+	}
 
-					p.SetSecuritygroupid(d.Id())
-					p.SetUsersecuritygrouplist(map[string]string{sg.Account: usg.(string)})
+	if sources, ok := rule["source_security_group"].(*schema.Set); ok && sources.Len() > 0 {
+		for _, source := range sources.List() {
+			source := source.(map[string]interface{})
 
-					// Create a single rule
-					err = createSecurityGroupRule(d, meta, rule, p, usg.(string))
-					if err != nil {
-						errs = multierror.Append(errs, err)
-					}
-				}
+			sg, err := retrieveSourceSecurityGroup(d, meta, source)
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
 			}
 
-			// If we have at least one UUID, we need to save the rule
-			if len(rule["uuids"].(map[string]interface{})) > 0 {
-				rules.Add(rule)
+			// Create a new parameter struct
+			switch rule["traffic_type"].(string) {
+			case "ingress":
+				p = cs.SecurityGroup.NewAuthorizeSecurityGroupIngressParams()
+			case "egress":
+				p = cs.SecurityGroup.NewAuthorizeSecurityGroupEgressParams()
 			}
 
-			<-sem
-		}(rule.(map[string]interface{}))
-	}
+			p.SetSecuritygroupid(d.Id())
+			p.SetUsersecuritygrouplist(map[string]string{sg.Account: sg.Name})
 
-	wg.Wait()
+			// Create a single rule
+			err = createSecurityGroupRule(d, meta, sem, rule, p, sourceSecurityGroupKey(source))
+			if err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+	}
 
 	return errs.ErrorOrNil()
 }
 
-func createSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}, p authorizeSecurityGroupParams, uuid string) error {
+func createSecurityGroupRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}, p authorizeSecurityGroupParams, uuid string) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 	uuids := rule["uuids"].(map[string]interface{})
 
@@ -248,7 +299,7 @@ func createSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[
 	p.SetProtocol(rule["protocol"].(string))
 
 	if rule["protocol"].(string) == "all" {
-		ruleID, err := createIngressOrEgressRule(cs, p)
+		ruleID, err := createIngressOrEgressRule(cs, sem, p)
 		if err != nil {
 			return err
 		}
@@ -262,7 +313,7 @@ func createSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[
 		p.SetIcmptype(rule["icmp_type"].(int))
 		p.SetIcmpcode(rule["icmp_code"].(int))
 
-		ruleID, err := createIngressOrEgressRule(cs, p)
+		ruleID, err := createIngressOrEgressRule(cs, sem, p)
 		if err != nil {
 			return err
 		}
@@ -285,25 +336,15 @@ func createSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[
 					continue
 				}
 
-				m := splitPorts.FindStringSubmatch(port.(string))
-
-				startPort, err := strconv.Atoi(m[1])
+				startPort, endPort, err := portAliasesOf(cs).parsePortRange(port.(string))
 				if err != nil {
 					return err
 				}
 
-				endPort := startPort
-				if m[2] != "" {
-					endPort, err = strconv.Atoi(m[2])
-					if err != nil {
-						return err
-					}
-				}
-
 				p.SetStartport(startPort)
 				p.SetEndport(endPort)
 
-				ruleID, err := createIngressOrEgressRule(cs, p)
+				ruleID, err := createIngressOrEgressRule(cs, sem, p)
 				if err != nil {
 					return err
 				}
@@ -320,23 +361,71 @@ func createSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[
 	return nil
 }
 
-func createIngressOrEgressRule(cs *cloudstack.CloudStackClient, p authorizeSecurityGroupParams) (string, error) {
+func createIngressOrEgressRule(cs *cloudstack.CloudStackClient, sem jobSlots, p authorizeSecurityGroupParams) (string, error) {
+	var jobID, ruleID string
+	var err error
+
+	sem.take()
 	switch p := p.(type) {
 	case *cloudstack.AuthorizeSecurityGroupIngressParams:
-		r, err := cs.SecurityGroup.AuthorizeSecurityGroupIngress(p)
-		if err != nil {
-			return "", err
+		var r *cloudstack.AuthorizeSecurityGroupIngressResponse
+		if r, err = jobs(cs).client().SecurityGroup.AuthorizeSecurityGroupIngress(p); err == nil {
+			jobID, ruleID = r.JobID, r.Ruleid
 		}
-		return r.Ruleid, nil
 	case *cloudstack.AuthorizeSecurityGroupEgressParams:
-		r, err := cs.SecurityGroup.AuthorizeSecurityGroupEgress(p)
-		if err != nil {
-			return "", err
+		var r *cloudstack.AuthorizeSecurityGroupEgressResponse
+		if r, err = jobs(cs).client().SecurityGroup.AuthorizeSecurityGroupEgress(p); err == nil {
+			jobID, ruleID = r.JobID, r.Ruleid
 		}
-		return r.Ruleid, nil
 	default:
-		return "", fmt.Errorf("Unknown authorize security group rule type: %v", p)
+		err = fmt.Errorf("Unknown authorize security group rule type: %v", p)
+	}
+	sem.release()
+
+	if err == nil {
+		err = jobs(cs).wait(jobID)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The ID of the rule is only part of the job result
+	if ruleID == "" {
+		return securityGroupRuleID(cs, jobID)
 	}
+
+	return ruleID, nil
+}
+
+// securityGroupRuleID returns the ID of the rule authorized by a finished
+// job. The job result is the security group with the authorized rule as its
+// only ingress or egress rule.
+func securityGroupRuleID(cs *cloudstack.CloudStackClient, jobID string) (string, error) {
+	b, err := jobs(cs).result(jobID)
+	if err != nil {
+		return "", err
+	}
+
+	type securityGroupRule struct {
+		Ruleid string `json:"ruleid"`
+	}
+
+	var result struct {
+		Securitygroup struct {
+			Ingressrule []securityGroupRule `json:"ingressrule"`
+			Egressrule  []securityGroupRule `json:"egressrule"`
+		} `json:"securitygroup"`
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return "", err
+	}
+
+	rules := append(result.Securitygroup.Ingressrule, result.Securitygroup.Egressrule...)
+	if len(rules) != 1 {
+		return "", fmt.Errorf("Unexpected number of rules in the result of job %s: %d", jobID, len(rules))
+	}
+
+	return rules[0].Ruleid, nil
 }
 
 func resourceCloudStackSecurityGroupRuleRead(d *schema.ResourceData, meta interface{}) error {
@@ -375,130 +464,227 @@ func resourceCloudStackSecurityGroupRuleRead(d *schema.ResourceData, meta interf
 			// First get any existing values
 			cidrList, cidrListOK := rule["cidr_list"].(*schema.Set)
 			usgList, usgListOk := rule["user_security_group_list"].(*schema.Set)
+			sources, sourcesOK := rule["source_security_group"].(*schema.Set)
+			ps, _ := rule["ports"].(*schema.Set)
 
 			// Then reset the values to a new empty set
 			rule["cidr_list"] = &schema.Set{F: schema.HashString}
 			rule["user_security_group_list"] = &schema.Set{F: schema.HashString}
+			rule["source_security_group"] = resourceCloudStackSecurityGroupRule().Schema["rule"].Elem.(*schema.Resource).
+				Schema["source_security_group"].ZeroValue().(*schema.Set)
+
+			// Create an empty schema.Set to hold all ports
+			ports := &schema.Set{F: schema.HashString}
 
 			if cidrListOK && cidrList.Len() > 0 {
 				for _, cidr := range cidrList.List() {
-					readSecurityGroupRule(sg, ruleIndex, rule, cidr.(string))
+					found := readSecurityGroupRule(sg, ruleIndex, rule, ps, ports, cidr.(string))
+					if len(found) > 0 {
+						rule["cidr_list"].(*schema.Set).Add(cidr)
+					}
 				}
 			}
 
 			if usgListOk && usgList.Len() > 0 {
 				for _, usg := range usgList.List() {
-					readSecurityGroupRule(sg, ruleIndex, rule, usg.(string))
+					found := readSecurityGroupRule(sg, ruleIndex, rule, ps, ports, usg.(string))
+					if len(found) > 0 {
+						rule["user_security_group_list"].(*schema.Set).Add(usg)
+					}
 				}
 			}
 
-			rules.Add(rule)
+			if sourcesOK && sources.Len() > 0 {
+				for _, source := range sources.List() {
+					source := source.(map[string]interface{})
+					found := readSecurityGroupRule(sg, ruleIndex, rule, ps, ports, sourceSecurityGroupKey(source))
+					if len(found) == 0 {
+						continue
+					}
+
+					// The rules have to reference the configured security group, which
+					// can have the same name as a security group of another account.
+					// The UUIDs are kept so the rules are revoked when updating.
+					if !matchSourceSecurityGroup(source, found) {
+						continue
+					}
+
+					rule["source_security_group"].(*schema.Set).Add(source)
+				}
+			}
+
+			if rule["protocol"].(string) == "tcp" || rule["protocol"].(string) == "udp" {
+				rule["ports"] = ports
+			}
+
+			// If there is at least one UUID, add this rule to the rules set
+			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				rules.Add(rule)
+			}
 		}
 	}
 
+	d.Set("rule", rules)
+
 	return nil
 }
 
-func readSecurityGroupRule(sg *cloudstack.SecurityGroup, ruleIndex map[string]int, rule map[string]interface{}, uuid string) {
+// readSecurityGroupRule reads the rules created for a single CIDR or security
+// group and returns the rules that still exist. The ports that still exist
+// are added to found.
+func readSecurityGroupRule(
+	sg *cloudstack.SecurityGroup,
+	ruleIndex map[string]int,
+	rule map[string]interface{},
+	ps *schema.Set,
+	found *schema.Set,
+	uuid string) []cloudstack.SecurityGroupRule {
 	uuids := rule["uuids"].(map[string]interface{})
 	sgRules := append(sg.Ingressrule, sg.Egressrule...)
 
-	if rule["protocol"].(string) == "all" {
-		id, ok := uuids[uuid+"all"]
+	var keys []string
+	switch rule["protocol"].(string) {
+	case "tcp", "udp":
+		if ps != nil {
+			for _, port := range ps.List() {
+				keys = append(keys, port.(string))
+			}
+		}
+	case "icmp":
+		keys = []string{"icmp"}
+	default:
+		keys = []string{"all"}
+	}
+
+	var matched []cloudstack.SecurityGroupRule
+	for _, key := range keys {
+		id, ok := uuids[uuid+key]
 		if !ok {
-			return
+			continue
 		}
 
 		// Get the rule
 		idx, ok := ruleIndex[id.(string)]
 		if !ok {
-			delete(uuids, uuid+"all")
-			return
+			delete(uuids, uuid+key)
+			continue
 		}
 
 		r := sgRules[idx]
 
 		// Update the values
-		if r.Cidr != "" {
-			rule["cidr_list"].(*schema.Set).Add(r.Cidr)
-		}
-
-		if r.Securitygroupname != "" {
-			rule["user_security_group_list"].(*schema.Set).Add(r.Securitygroupname)
+		rule["protocol"] = r.Protocol
+		switch r.Protocol {
+		case "icmp":
+			rule["icmp_type"] = r.Icmptype
+			rule["icmp_code"] = r.Icmpcode
+		case "tcp", "udp":
+			found.Add(key)
 		}
 
-		rule["protocol"] = r.Protocol
+		matched = append(matched, r)
 	}
 
-	if rule["protocol"].(string) == "icmp" {
-		id, ok := uuids[uuid+"icmp"]
-		if !ok {
-			return
-		}
-
-		// Get the rule
-		idx, ok := ruleIndex[id.(string)]
-		if !ok {
-			delete(uuids, uuid+"icmp")
-			return
-		}
+	return matched
+}
 
-		r := sgRules[idx]
+// retrieveSourceSecurityGroup returns the security group referenced by a
+// source_security_group block, which can be owned by another account.
+func retrieveSourceSecurityGroup(d *schema.ResourceData, meta interface{}, source map[string]interface{}) (*cloudstack.SecurityGroup, error) {
+	cs := meta.(*cloudstack.CloudStackClient)
 
-		// Update the values
-		if r.Cidr != "" {
-			rule["cidr_list"].(*schema.Set).Add(r.Cidr)
-		}
+	p := cs.SecurityGroup.NewListSecurityGroupsParams()
+	p.SetListall(true)
 
-		if r.Securitygroupname != "" {
-			rule["user_security_group_list"].(*schema.Set).Add(r.Securitygroupname)
-		}
+	if id := source["id"].(string); id != "" {
+		p.SetId(id)
+	} else {
+		p.SetSecuritygroupname(source["name"].(string))
+	}
 
-		rule["protocol"] = r.Protocol
-		rule["icmp_type"] = r.Icmptype
-		rule["icmp_code"] = r.Icmpcode
+	account := source["account"].(string)
+	if account != "" {
+		p.SetAccount(account)
 	}
 
-	// If protocol is tcp or udp, loop through all ports
-	if rule["protocol"].(string) == "tcp" || rule["protocol"].(string) == "udp" {
-		if ps := rule["ports"].(*schema.Set); ps.Len() > 0 {
+	if domain := source["domain"].(string); domain != "" {
+		domainid, e := retrieveID(cs, "domain", domain)
+		if e != nil {
+			return nil, e.Error()
+		}
+		p.SetDomainid(domainid)
+	}
 
-			// Create an empty schema.Set to hold all ports
-			ports := &schema.Set{F: schema.HashString}
+	// Without an explicit owner, the security group is looked up in the
+	// project of the rules
+	project := source["project"].(string)
+	if project == "" && account == "" {
+		project = d.Get("project").(string)
+	}
+	if project != "" {
+		projectid, e := retrieveID(cs, "project", project)
+		if e != nil {
+			return nil, e.Error()
+		}
+		p.SetProjectid(projectid)
+	}
 
-			// Loop through all ports and retrieve their info
-			for _, port := range ps.List() {
-				id, ok := uuids[uuid+port.(string)]
-				if !ok {
-					continue
-				}
+	l, err := cs.SecurityGroup.ListSecurityGroups(p)
+	if err != nil {
+		return nil, err
+	}
 
-				// Get the rule
-				idx, ok := ruleIndex[id.(string)]
-				if !ok {
-					delete(uuids, uuid+port.(string))
-					continue
-				}
+	name := sourceSecurityGroupKey(source)
+	switch l.Count {
+	case 0:
+		return nil, fmt.Errorf("Source security group %s not found", strings.TrimSuffix(name, "/"))
+	case 1:
+		return l.SecurityGroups[0], nil
+	default:
+		var owners []string
+		for _, sg := range l.SecurityGroups {
+			owners = append(owners, sg.Account)
+		}
+		sort.Strings(owners)
+		return nil, fmt.Errorf(
+			"Source security group %s is ambiguous, it is found in the accounts: %s. "+
+				"Please set the account and domain", strings.TrimSuffix(name, "/"), strings.Join(owners, ", "))
+	}
+}
 
-				r := sgRules[idx]
+// sourceSecurityGroupKey returns the key used to store the UUIDs of the rules
+// created for a source_security_group block.
+func sourceSecurityGroupKey(source map[string]interface{}) string {
+	var parts []string
+	for _, k := range []string{"project", "domain", "account"} {
+		if v, _ := source[k].(string); v != "" {
+			parts = append(parts, v)
+		}
+	}
 
-				// Create a set with all CIDR's
-				cidrs := &schema.Set{F: schema.HashString}
-				for _, cidr := range strings.Split(r.Cidr, ",") {
-					cidrs.Add(cidr)
-				}
+	if id, _ := source["id"].(string); id != "" {
+		parts = append(parts, id)
+	} else {
+		name, _ := source["name"].(string)
+		parts = append(parts, name)
+	}
 
-				// Update the values
-				rule["protocol"] = r.Protocol
-				ports.Add(port)
-			}
+	return strings.Join(parts, "/") + "/"
+}
 
-			// If there is at least one port found, add this rule to the rules set
-			if ports.Len() > 0 {
-				rule["ports"] = ports
-			}
+// matchSourceSecurityGroup reports whether the rules reference the security
+// group of a source_security_group block.
+func matchSourceSecurityGroup(source map[string]interface{}, rules []cloudstack.SecurityGroupRule) bool {
+	for _, r := range rules {
+		if name := source["name"].(string); name != "" && r.Securitygroupname != name {
+			return false
+		}
+		if account := source["account"].(string); account != "" && r.Account != account {
+			return false
 		}
 	}
+
+	return true
 }
 
 func resourceCloudStackSecurityGroupRuleUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -563,86 +749,234 @@ func resourceCloudStackSecurityGroupRuleDelete(d *schema.ResourceData, meta inte
 }
 
 func deleteSecurityGroupRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, ors *schema.Set) error {
-	var errs *multierror.Error
-
-	var wg sync.WaitGroup
-	wg.Add(ors.Len())
+	return applyFirewallRules(d, rules, ors, "deleting", func(sem jobSlots, rule map[string]interface{}) error {
+		return deleteSecurityGroupRule(d, meta, sem, rule)
+	})
+}
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
-	for _, rule := range ors.List() {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
+func deleteSecurityGroupRule(d *schema.ResourceData, meta interface{}, sem jobSlots, rule map[string]interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
 
-		go func(rule map[string]interface{}) {
-			defer wg.Done()
-			sem <- struct{}{}
+	return deleteFirewallRuleIDs(rule, func(id string) error {
+		var jobID string
+		var err error
 
-			// Create a single rule
-			err := deleteSecurityGroupRule(d, meta, rule)
-			if err != nil {
-				errs = multierror.Append(errs, err)
+		sem.take()
+		switch rule["traffic_type"].(string) {
+		case "ingress":
+			p := cs.SecurityGroup.NewRevokeSecurityGroupIngressParams(id)
+			var r *cloudstack.RevokeSecurityGroupIngressResponse
+			if r, err = jobs(cs).client().SecurityGroup.RevokeSecurityGroupIngress(p); err == nil {
+				jobID = r.JobID
 			}
-
-			// If we have at least one UUID, we need to save the rule
-			if len(rule["uuids"].(map[string]interface{})) > 0 {
-				rules.Add(rule)
+		case "egress":
+			p := cs.SecurityGroup.NewRevokeSecurityGroupEgressParams(id)
+			var r *cloudstack.RevokeSecurityGroupEgressResponse
+			if r, err = jobs(cs).client().SecurityGroup.RevokeSecurityGroupEgress(p); err == nil {
+				jobID = r.JobID
 			}
+		}
+		sem.release()
+
+		if err == nil {
+			err = jobs(cs).wait(jobID)
+		}
 
-			<-sem
-		}(rule.(map[string]interface{}))
+		return err
+	})
+}
+
+func resourceCloudStackSecurityGroupRuleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// Reject duplicate and overlapping rules before any of them are created
+	if !portRulesKnown(d, "rule") {
+		return nil
 	}
 
-	wg.Wait()
+	var rules []portRule
+	for _, rule := range d.Get("rule").(*schema.Set).List() {
+		rule := rule.(map[string]interface{})
 
-	return errs.ErrorOrNil()
+		sources := append(setToStrings(rule["cidr_list"]), setToStrings(rule["user_security_group_list"])...)
+		if ss, ok := rule["source_security_group"].(*schema.Set); ok {
+			for _, source := range ss.List() {
+				sources = append(sources, sourceSecurityGroupKey(source.(map[string]interface{})))
+			}
+		}
+		rules = append(rules, expandPortRules(rule, rule["traffic_type"].(string), "", sources)...)
+	}
+
+	return checkPortRules(portAliasesOf(meta.(*cloudstack.CloudStackClient)), rules, false)
 }
 
-func deleteSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
+func resourceCloudStackSecurityGroupRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	cs := meta.(*cloudstack.CloudStackClient)
-	uuids := rule["uuids"].(map[string]interface{})
 
-	for k, id := range uuids {
-		// We don't care about the count here, so just continue
-		if k == "%" {
-			continue
+	// Try to split the ID to extract the optional project name.
+	s := strings.SplitN(d.Id(), "/", 2)
+	if len(s) == 2 {
+		d.Set("project", s[0])
+	}
+
+	securityGroupID := s[len(s)-1]
+	d.SetId(securityGroupID)
+	d.Set("security_group_id", securityGroupID)
+
+	// Get the security group details
+	sg, count, err := cs.SecurityGroup.GetSecurityGroupByID(
+		securityGroupID,
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		if count == 0 {
+			return nil, fmt.Errorf("Security group with ID %s does not exist", securityGroupID)
 		}
+		return nil, err
+	}
 
-		var err error
-		switch rule["traffic_type"].(string) {
-		case "ingress":
-			p := cs.SecurityGroup.NewRevokeSecurityGroupIngressParams(id.(string))
-			_, err = cs.SecurityGroup.RevokeSecurityGroupIngress(p)
-		case "egress":
-			p := cs.SecurityGroup.NewRevokeSecurityGroupEgressParams(id.(string))
-			_, err = cs.SecurityGroup.RevokeSecurityGroupEgress(p)
+	// Create authorizes every CIDR and security group of a rule separately,
+	// so first collect the rules of each source into a single rule
+	sources := newImportedRules()
+
+	sourceSchema := resourceCloudStackSecurityGroupRule().Schema["rule"].Elem.(*schema.Resource).
+		Schema["source_security_group"]
+
+	addRule := func(r cloudstack.SecurityGroupRule, trafficType string) {
+		source := r.Cidr
+		if r.Securitygroupname != "" {
+			source = r.Securitygroupname
 		}
 
-		if err != nil {
-			// This is a very poor way to be told the ID does no longer exist :(
-			if strings.Contains(err.Error(), fmt.Sprintf(
-				"Invalid parameter id value=%s due to incorrect long value format, "+
-					"or entity does not exist", id.(string))) {
-				delete(uuids, k)
-				continue
+		// Security groups of another account are imported with their account
+		var crossAccount map[string]interface{}
+		if r.Securitygroupname != "" && r.Account != "" && r.Account != sg.Account {
+			crossAccount = map[string]interface{}{
+				"name":    r.Securitygroupname,
+				"id":      "",
+				"account": r.Account,
+				"domain":  "",
+				"project": "",
 			}
+			source = sourceSecurityGroupKey(crossAccount)
+		}
 
-			return err
+		key := fmt.Sprintf("%s/%s/%s", trafficType, r.Protocol, source)
+		if r.Protocol == "icmp" {
+			key = fmt.Sprintf("%s/%d/%d", key, r.Icmptype, r.Icmpcode)
 		}
 
-		// Delete the UUID of this rule
-		delete(uuids, k)
+		rule := sources.group(key, func() map[string]interface{} {
+			rule := map[string]interface{}{
+				"traffic_type":             trafficType,
+				"protocol":                 r.Protocol,
+				"cidr_list":                &schema.Set{F: schema.HashString},
+				"user_security_group_list": &schema.Set{F: schema.HashString},
+				"source_security_group":    sourceSchema.ZeroValue().(*schema.Set),
+			}
+
+			if crossAccount != nil {
+				rule["source_security_group"].(*schema.Set).Add(crossAccount)
+			} else if r.Securitygroupname != "" {
+				rule["user_security_group_list"].(*schema.Set).Add(r.Securitygroupname)
+			} else {
+				rule["cidr_list"].(*schema.Set).Add(r.Cidr)
+			}
+
+			return rule
+		})
+
+		uuids := rule["uuids"].(map[string]interface{})
+
+		switch r.Protocol {
+		case "all":
+			uuids[source+"all"] = r.Ruleid
+		case "icmp":
+			rule["icmp_type"] = r.Icmptype
+			rule["icmp_code"] = r.Icmpcode
+			uuids[source+"icmp"] = r.Ruleid
+		default:
+			sources.addPort(rule, source, r.Startport, r.Endport, r.Ruleid)
+		}
 	}
 
-	return nil
+	for _, r := range sg.Ingressrule {
+		addRule(r, "ingress")
+	}
+	for _, r := range sg.Egressrule {
+		addRule(r, "egress")
+	}
+
+	// Then merge the sources that share the same ports into one rule, as if
+	// they were configured in a single rule block
+	grouped := make(map[string]map[string]interface{})
+
+	for _, rule := range sources.list() {
+		var ports []string
+		for _, port := range rule["ports"].(*schema.Set).List() {
+			ports = append(ports, port.(string))
+		}
+		sort.Strings(ports)
+
+		groupKey := fmt.Sprintf("%s/%s/%v/%v/%s",
+			rule["traffic_type"], rule["protocol"], rule["icmp_type"], rule["icmp_code"], strings.Join(ports, ","))
+
+		existing, ok := grouped[groupKey]
+		if !ok {
+			grouped[groupKey] = rule
+			continue
+		}
+
+		for _, cidr := range rule["cidr_list"].(*schema.Set).List() {
+			existing["cidr_list"].(*schema.Set).Add(cidr)
+		}
+		for _, usg := range rule["user_security_group_list"].(*schema.Set).List() {
+			existing["user_security_group_list"].(*schema.Set).Add(usg)
+		}
+		for _, source := range rule["source_security_group"].(*schema.Set).List() {
+			existing["source_security_group"].(*schema.Set).Add(source)
+		}
+		for k, v := range rule["uuids"].(map[string]interface{}) {
+			existing["uuids"].(map[string]interface{})[k] = v
+		}
+	}
+
+	// Create an empty schema.Set to hold all rules
+	rules := resourceCloudStackSecurityGroupRule().Schema["rule"].ZeroValue().(*schema.Set)
+	for _, rule := range grouped {
+		rules.Add(rule)
+	}
+
+	d.Set("rule", rules)
+	d.Set("parallelism", 2)
+
+	return []*schema.ResourceData{d}, nil
 }
 
-func verifySecurityGroupRuleParams(d *schema.ResourceData, rule map[string]interface{}) error {
+func verifySecurityGroupRuleParams(d *schema.ResourceData, pa portAliases, rule map[string]interface{}) error {
 	cidrList, cidrListOK := rule["cidr_list"].(*schema.Set)
 	usgList, usgListOK := rule["user_security_group_list"].(*schema.Set)
+	sources, sourcesOK := rule["source_security_group"].(*schema.Set)
 
-	if (!cidrListOK || cidrList.Len() == 0) && (!usgListOK || usgList.Len() == 0) {
+	if (!cidrListOK || cidrList.Len() == 0) && (!usgListOK || usgList.Len() == 0) &&
+		(!sourcesOK || sources.Len() == 0) {
 		return fmt.Errorf(
-			"You must supply at least one 'cidr_list' or `user_security_group_ids` entry")
+			"You must supply at least one 'cidr_list', `user_security_group_list` or `source_security_group` entry")
+	}
+
+	if sourcesOK {
+		for _, source := range sources.List() {
+			source := source.(map[string]interface{})
+
+			name, id := source["name"].(string), source["id"].(string)
+			if (name == "") == (id == "") {
+				return fmt.Errorf(
+					"You must supply either a name or an id for each source_security_group")
+			}
+
+			if source["project"].(string) != "" && (source["account"].(string) != "" || source["domain"].(string) != "") {
+				return fmt.Errorf(
+					"Parameter project cannot be combined with account or domain in a source_security_group")
+			}
+		}
 	}
 
 	protocol := rule["protocol"].(string)
@@ -661,10 +995,8 @@ func verifySecurityGroupRuleParams(d *schema.ResourceData, rule map[string]inter
 	case "tcp", "udp":
 		if ports, ok := rule["ports"].(*schema.Set); ok {
 			for _, port := range ports.List() {
-				m := splitPorts.FindStringSubmatch(port.(string))
-				if m == nil {
-					return fmt.Errorf(
-						"%q is not a valid port value. Valid options are '80' or '80-90'", port.(string))
+				if _, _, err := pa.parsePortRange(port.(string)); err != nil {
+					return err
 				}
 			}
 		} else {
@@ -147,6 +147,56 @@ func TestAccCloudStackSecurityGroupRule_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackSecurityGroupRule_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSecurityGroupRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSecurityGroupRule_update,
+			},
+
+			{
+				ResourceName:      "cloudstack_security_group_rule.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccCloudStackSecurityGroupRule_sourceSecurityGroup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSecurityGroupRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSecurityGroupRule_sourceSecurityGroup,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackSecurityGroupRulesExist("cloudstack_security_group.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_security_group_rule.foo", "rule.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_security_group_rule.foo", "rule.*", map[string]string{
+							"protocol":                     "tcp",
+							"ports.#":                      "1",
+							"source_security_group.#":      "1",
+							"source_security_group.0.name": "terraform-security-group-bar",
+						}),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cloudstack_security_group_rule.foo", "rule.*", map[string]string{
+							"protocol":                "icmp",
+							"traffic_type":            "egress",
+							"source_security_group.#": "1",
+						}),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckCloudStackSecurityGroupRulesExist(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -308,3 +358,38 @@ resource "cloudstack_security_group_rule" "foo" {
 
 	depends_on = ["cloudstack_security_group.bar"]
 }`
+
+const testAccCloudStackSecurityGroupRule_sourceSecurityGroup = `
+resource "cloudstack_security_group" "foo" {
+  name = "terraform-security-group-foo"
+  description = "terraform-security-group-text"
+}
+
+resource "cloudstack_security_group" "bar" {
+  name = "terraform-security-group-bar"
+  description = "terraform-security-group-text"
+}
+
+resource "cloudstack_security_group_rule" "foo" {
+  security_group_id = cloudstack_security_group.foo.id
+
+  rule {
+    protocol = "tcp"
+    ports = ["https"]
+
+    source_security_group {
+      name = cloudstack_security_group.bar.name
+    }
+  }
+
+  rule {
+    protocol = "icmp"
+    icmp_type = "-1"
+    icmp_code = "-1"
+    traffic_type = "egress"
+
+    source_security_group {
+      id = cloudstack_security_group.bar.id
+    }
+  }
+}`
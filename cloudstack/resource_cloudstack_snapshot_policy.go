@@ -182,15 +182,7 @@ func resourceCloudstackSnapshotPolicyRead(d *schema.ResourceData, meta interface
 	d.Set("timezone", snapshotPolicy.Timezone)
 
 	if snapshotPolicy.Zone != nil {
-		zoneIDs := []string{}
-		for _, zone := range snapshotPolicy.Zone {
-			if zoneMap, ok := zone.(map[string]interface{}); ok {
-				if id, ok := zoneMap["id"].(string); ok {
-					zoneIDs = append(zoneIDs, id)
-				}
-			}
-		}
-		d.Set("zone_ids", zoneIDs)
+		d.Set("zone_ids", snapshotPolicyZoneIDs(snapshotPolicy))
 	} else {
 		d.Set("zone_ids", nil)
 	}
@@ -244,3 +236,16 @@ func resourceCloudstackSnapshotPolicyDelete(d *schema.ResourceData, meta interfa
 
 	return nil
 }
+
+// snapshotPolicyZoneIDs returns the IDs of the zones snapshots are copied to
+func snapshotPolicyZoneIDs(sp *cloudstack.SnapshotPolicy) []string {
+	zoneIDs := []string{}
+	for _, zone := range sp.Zone {
+		if zoneMap, ok := zone.(map[string]interface{}); ok {
+			if id, ok := zoneMap["id"].(string); ok {
+				zoneIDs = append(zoneIDs, id)
+			}
+		}
+	}
+	return zoneIDs
+}
@@ -0,0 +1,243 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackSSLCertificate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackSSLCertificateCreate,
+		Read:   resourceCloudStackSSLCertificateRead,
+		Update: resourceCloudStackSSLCertificateUpdate,
+		Delete: resourceCloudStackSSLCertificateDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+		// Changing the private key or its password uploads a new certificate,
+		// unless they are unknown because the certificate was imported
+		CustomizeDiff: forceNewUnlessUnset("private_key", "password"),
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"certificate": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				StateFunc:        normalizeCertificate,
+				DiffSuppressFunc: suppressCertificateWhitespace,
+			},
+
+			"private_key": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+
+			"certificate_chain": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				StateFunc:        normalizeCertificate,
+				DiffSuppressFunc: suppressCertificateWhitespace,
+			},
+
+			"password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+
+			"account": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"domain_id"},
+			},
+
+			"domain_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"project": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"account"},
+			},
+
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"loadbalancer_rule_ids": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceCloudStackSSLCertificateCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	name := d.Get("name").(string)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewUploadSslCertParams(
+		d.Get("certificate").(string),
+		name,
+		d.Get("private_key").(string),
+	)
+
+	if chain, ok := d.GetOk("certificate_chain"); ok {
+		p.SetCertchain(chain.(string))
+	}
+
+	if password, ok := d.GetOk("password"); ok {
+		p.SetPassword(password.(string))
+	}
+
+	if account, ok := d.GetOk("account"); ok {
+		p.SetAccount(account.(string))
+	}
+
+	if domainid, ok := d.GetOk("domain_id"); ok {
+		p.SetDomainid(domainid.(string))
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	r, err := cs.LoadBalancer.UploadSslCert(p)
+	if err != nil {
+		return fmt.Errorf("Error uploading SSL certificate %s: %s", name, err)
+	}
+
+	d.SetId(r.Id)
+
+	return resourceCloudStackSSLCertificateRead(d, meta)
+}
+
+func resourceCloudStackSSLCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewListSslCertsParams()
+	p.SetCertid(d.Id())
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	l, err := cs.LoadBalancer.ListSslCerts(p)
+	if err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			log.Printf("[DEBUG] SSL certificate %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving SSL certificate %s: %s", d.Id(), err)
+	}
+
+	if l.Count == 0 {
+		log.Printf("[DEBUG] SSL certificate %s does no longer exist", d.Get("name").(string))
+		d.SetId("")
+		return nil
+	}
+
+	cert := l.SslCerts[0]
+
+	d.Set("name", cert.Name)
+	d.Set("certificate", normalizeCertificate(cert.Certificate))
+	d.Set("certificate_chain", normalizeCertificate(cert.Certchain))
+	d.Set("account", cert.Account)
+	d.Set("domain_id", cert.Domainid)
+	d.Set("fingerprint", cert.Fingerprint)
+	d.Set("loadbalancer_rule_ids", cert.Loadbalancerrulelist)
+
+	setValueOrID(d, "project", cert.Project, cert.Projectid)
+
+	return nil
+}
+
+func resourceCloudStackSSLCertificateUpdate(d *schema.ResourceData, meta interface{}) error {
+	// The only in place updates store the private key and password of an
+	// imported certificate, which are not sent to CloudStack
+	return resourceCloudStackSSLCertificateRead(d, meta)
+}
+
+func resourceCloudStackSSLCertificateDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Create a new parameter struct
+	p := cs.LoadBalancer.NewDeleteSslCertParams(d.Id())
+
+	if _, err := cs.LoadBalancer.DeleteSslCert(p); err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting SSL certificate %s: %s", d.Get("name").(string), err)
+	}
+
+	return nil
+}
+
+// normalizeCertificate strips leading and trailing whitespace from PEM data,
+// so the configured and the returned certificate are stored the same way.
+func normalizeCertificate(v interface{}) string {
+	return strings.TrimSpace(v.(string))
+}
+
+// suppressCertificateWhitespace ignores differences in leading and trailing
+// whitespace, as CloudStack returns PEM data without the trailing newline.
+func suppressCertificateWhitespace(k, old, new string, d *schema.ResourceData) bool {
+	return strings.TrimSpace(old) == strings.TrimSpace(new)
+}
@@ -35,6 +35,12 @@ func resourceCloudStackTemplate() *schema.Resource {
 		Read:   resourceCloudStackTemplateRead,
 		Update: resourceCloudStackTemplateUpdate,
 		Delete: resourceCloudStackTemplateDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackTemplateImport,
+		},
+		// Changing the URL registers a new template, unless it is unknown
+		// because the template was imported
+		CustomizeDiff: forceNewUnlessUnset("url"),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -67,7 +73,6 @@ func resourceCloudStackTemplate() *schema.Resource {
 			"url": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 
 			"project": {
@@ -421,6 +426,13 @@ func resourceCloudStackTemplateDelete(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+func resourceCloudStackTemplateImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// Set is_ready_timeout to its default, as it is only used during
+	// creation and can not be read back from the API.
+	d.Set("is_ready_timeout", 300)
+	return importStatePassthrough(d, meta)
+}
+
 func verifyTemplateParams(d *schema.ResourceData) error {
 	format := d.Get("format").(string)
 	if format != "OVA" && format != "QCOW2" && format != "RAW" && format != "VHD" && format != "VMDK" {
@@ -471,6 +483,11 @@ func readUserdataFromTemplate(d *schema.ResourceData, template *cloudstack.Templ
 		}
 	}
 
+	// When importing there is no existing link, so use the policy reported by the API
+	if _, ok := userdataLink["userdata_policy"]; !ok && template.Userdatapolicy != "" {
+		userdataLink["userdata_policy"] = template.Userdatapolicy
+	}
+
 	d.Set("userdata_link", []interface{}{userdataLink})
 	return nil
 }
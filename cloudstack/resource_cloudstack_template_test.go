@@ -25,6 +25,7 @@ import (
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
@@ -88,6 +89,46 @@ func TestAccCloudStackTemplate_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackTemplate_import(t *testing.T) {
+	if cloudStackTemplateURL == "" {
+		t.Skip("This test requires an upload URL")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackTemplateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackTemplate_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_template.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"url"},
+			},
+
+			{
+				ResourceName:       "cloudstack_template.foo",
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+
+			{
+				// The unknown URL of an imported template is stored in place
+				Config: testAccCloudStackTemplate_basic,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("cloudstack_template.foo", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+		},
+	})
+}
+
 func testAccCheckCloudStackTemplateExists(
 	n string, template *cloudstack.Template) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -22,6 +22,8 @@ package cloudstack
 import (
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -31,23 +33,97 @@ func resourceCloudStackVolume() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceCloudStackVolumeCreate,
 		Read:   resourceCloudStackVolumeRead,
+		Update: resourceCloudStackVolumeUpdate,
 		Delete: resourceCloudStackVolumeDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackVolumeImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"disk_offering_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: []string{"disk_offering_id", "url"},
+			},
+			"zone_id": {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
 			},
-			"zone_id": {
+			"size": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"url"},
+			},
+			"min_iops": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"url"},
+			},
+			"max_iops": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"url"},
+			},
+			"shrink_ok": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"storage_pool": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"url"},
+			},
+			"url": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				ForceNew: true,
+			},
+			"format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"url"},
+			},
+			"checksum": {
+				Type:     schema.TypeString,
+				Optional: true,
 				ForceNew: true,
 			},
+			"upload_timeout": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  3600,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"delete_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"virtual_machine_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tags": tagsSchema(),
 		},
 	}
 }
@@ -55,34 +131,144 @@ func resourceCloudStackVolume() *schema.Resource {
 func resourceCloudStackVolumeCreate(d *schema.ResourceData, meta interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 	name := d.Get("name").(string)
-	disk_offering_id := d.Get("disk_offering_id").(string)
-	zone_id := d.Get("zone_id").(string)
 
-	//Create a new parameter struct
-	p := cs.Volume.NewCreateVolumeParams()
-	p.SetDiskofferingid(disk_offering_id)
-	p.SetZoneid(zone_id)
-	p.SetName(name)
+	if _, ok := d.GetOk("url"); ok {
+		if err := resourceCloudStackVolumeUpload(d, meta); err != nil {
+			return err
+		}
+	} else {
+		//Create a new parameter struct
+		p := cs.Volume.NewCreateVolumeParams()
+		p.SetDiskofferingid(d.Get("disk_offering_id").(string))
+		p.SetZoneid(d.Get("zone_id").(string))
+		p.SetName(name)
+
+		if v, ok := d.GetOk("size"); ok {
+			p.SetSize(int64(v.(int)))
+		}
 
-	log.Printf("[DEBUG] Creating Volume %s", name)
-	v, err := cs.Volume.CreateVolume(p)
+		if v, ok := d.GetOk("min_iops"); ok {
+			p.SetMiniops(int64(v.(int)))
+		}
 
-	if err != nil {
-		return err
+		if v, ok := d.GetOk("max_iops"); ok {
+			p.SetMaxiops(int64(v.(int)))
+		}
+
+		// If a storage pool is supplied, create the volume on that pool
+		if v, ok := d.GetOk("storage_pool"); ok {
+			storageid, e := retrieveID(cs, "storage_pool", v.(string))
+			if e != nil {
+				return e.Error()
+			}
+			p.SetStorageid(storageid)
+		}
+
+		// If there is a project supplied, we retrieve and set the project id
+		if err := setProjectid(p, cs, d); err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] Creating Volume %s", name)
+		v, err := cs.Volume.CreateVolume(p)
+		if err != nil {
+			return fmt.Errorf("Error creating volume %s: %s", name, err)
+		}
+
+		log.Printf("[DEBUG] Volume %s successfully created", name)
+		d.SetId(v.Id)
+	}
+
+	// Set delete protection using UpdateVolume
+	if v, ok := d.GetOk("delete_protection"); ok {
+		p := cs.Volume.NewUpdateVolumeParams()
+		p.SetId(d.Id())
+		p.SetDeleteprotection(v.(bool))
+
+		if _, err := cs.Volume.UpdateVolume(p); err != nil {
+			return fmt.Errorf(
+				"Error updating the delete protection for volume %s: %s", name, err)
+		}
 	}
 
-	log.Printf("[DEBUG] Volume %s successfully created", name)
-	d.SetId(v.Id)
+	// Set tags if necessary
+	if err := setTags(cs, d, "Volume"); err != nil {
+		return fmt.Errorf("Error setting tags on volume %s: %s", name, err)
+	}
 
 	return resourceCloudStackVolumeRead(d, meta)
 }
+
+func resourceCloudStackVolumeUpload(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+	name := d.Get("name").(string)
+
+	// Create a new parameter struct
+	p := cs.Volume.NewUploadVolumeParams(
+		d.Get("format").(string),
+		name,
+		d.Get("url").(string),
+		d.Get("zone_id").(string),
+	)
+
+	if v, ok := d.GetOk("checksum"); ok {
+		p.SetChecksum(v.(string))
+	}
+
+	if v, ok := d.GetOk("disk_offering_id"); ok {
+		p.SetDiskofferingid(v.(string))
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Uploading Volume %s", name)
+	r, err := cs.Volume.UploadVolume(p)
+	if err != nil {
+		return fmt.Errorf("Error uploading volume %s: %s", name, err)
+	}
+
+	d.SetId(r.Id)
+
+	// Wait until the volume is uploaded, or timeout with an error...
+	currentTime := time.Now().Unix()
+	timeout := int64(d.Get("upload_timeout").(int))
+	for {
+		v, _, err := cs.Volume.GetVolumeByID(
+			d.Id(),
+			cloudstack.WithProject(d.Get("project").(string)),
+		)
+		if err != nil {
+			return err
+		}
+
+		switch v.State {
+		case "Uploaded", "Ready":
+			log.Printf("[DEBUG] Volume %s successfully uploaded", name)
+			return nil
+		case "UploadError", "UploadAbandoned":
+			return fmt.Errorf("Error uploading volume %s: volume is in state %s", name, v.State)
+		}
+
+		if time.Now().Unix()-currentTime > timeout {
+			return fmt.Errorf("Timeout while waiting for volume %s to be uploaded", name)
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+}
+
 func resourceCloudStackVolumeRead(d *schema.ResourceData, meta interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 	log.Printf("[DEBUG] Retrieving Volume %s", d.Get("name").(string))
 
 	// Get the Volume details
-	v, count, err := cs.Volume.GetVolumeByName(d.Get("name").(string))
-
+	v, count, err := cs.Volume.GetVolumeByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
 	if err != nil {
 		if count == 0 {
 			log.Printf("[DEBUG] Volume %s does no longer exist", d.Get("name").(string))
@@ -92,14 +278,106 @@ func resourceCloudStackVolumeRead(d *schema.ResourceData, meta interface{}) erro
 		return err
 	}
 
-	d.SetId(v.Id)
 	d.Set("name", v.Name)
 	d.Set("disk_offering_id", v.Diskofferingid)
 	d.Set("zone_id", v.Zoneid)
+	d.Set("size", int(v.Size/(1024*1024*1024))) // Needed to get GB's again
+	d.Set("min_iops", int(v.Miniops))
+	d.Set("max_iops", int(v.Maxiops))
+	d.Set("delete_protection", v.Deleteprotection)
+	d.Set("state", v.State)
+	d.Set("virtual_machine_id", v.Virtualmachineid)
+
+	tags := make(map[string]interface{})
+	for _, tag := range v.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	d.Set("tags", tags)
+
+	setValueOrID(d, "project", v.Project, v.Projectid)
+	setValueOrID(d, "storage_pool", v.Storage, v.Storageid)
 
 	return nil
 }
 
+func resourceCloudStackVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+	name := d.Get("name").(string)
+
+	if d.HasChange("name") {
+		p := cs.Volume.NewUpdateVolumeParams()
+		p.SetId(d.Id())
+		p.SetName(name)
+
+		if _, err := cs.Volume.UpdateVolume(p); err != nil {
+			return fmt.Errorf("Error updating the name of volume %s: %s", name, err)
+		}
+	}
+
+	if d.HasChanges("disk_offering_id", "size", "min_iops", "max_iops") {
+		// Create a new parameter struct
+		p := cs.Volume.NewResizeVolumeParams(d.Id())
+
+		if d.HasChange("disk_offering_id") {
+			p.SetDiskofferingid(d.Get("disk_offering_id").(string))
+		}
+
+		if d.HasChange("size") {
+			p.SetSize(int64(d.Get("size").(int)))
+		}
+
+		if d.HasChange("min_iops") {
+			p.SetMiniops(int64(d.Get("min_iops").(int)))
+		}
+
+		if d.HasChange("max_iops") {
+			p.SetMaxiops(int64(d.Get("max_iops").(int)))
+		}
+
+		// Set the shrink bit
+		p.SetShrinkok(d.Get("shrink_ok").(bool))
+
+		if _, err := cs.Volume.ResizeVolume(p); err != nil {
+			return fmt.Errorf("Error resizing volume %s: %s", name, err)
+		}
+	}
+
+	if d.HasChange("storage_pool") {
+		storageid, e := retrieveID(cs, "storage_pool", d.Get("storage_pool").(string))
+		if e != nil {
+			return e.Error()
+		}
+
+		// A live migration is needed when the volume is attached
+		attached := d.Get("virtual_machine_id").(string) != ""
+
+		if err := migrateVolume(cs, d, storageid, attached); err != nil {
+			return fmt.Errorf("Error migrating volume %s: %s", name, err)
+		}
+	}
+
+	// Check is the tags have changed and if so, update the tags
+	if d.HasChange("tags") {
+		if err := updateTags(cs, d, "Volume"); err != nil {
+			return fmt.Errorf("Error updating tags on volume %s: %s", name, err)
+		}
+	}
+
+	// Check if the delete protection has changed and if so, update the delete protection
+	if d.HasChange("delete_protection") {
+		p := cs.Volume.NewUpdateVolumeParams()
+		p.SetId(d.Id())
+		p.SetDeleteprotection(d.Get("delete_protection").(bool))
+
+		if _, err := cs.Volume.UpdateVolume(p); err != nil {
+			return fmt.Errorf(
+				"Error updating the delete protection for volume %s: %s", name, err)
+		}
+	}
+
+	return resourceCloudStackVolumeRead(d, meta)
+}
+
 func resourceCloudStackVolumeDelete(d *schema.ResourceData, meta interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 
@@ -108,8 +386,51 @@ func resourceCloudStackVolumeDelete(d *schema.ResourceData, meta interface{}) er
 	_, err := cs.Volume.DeleteVolume(p)
 
 	if err != nil {
+		// This is a very poor way to be told the ID does no longer exist :(
+		if strings.Contains(err.Error(), fmt.Sprintf(
+			"Invalid parameter id value=%s due to incorrect long value format, "+
+				"or entity does not exist", d.Id())) {
+			return nil
+		}
+
 		return fmt.Errorf("Error deleting Volume: %s", err)
 	}
 
 	return nil
 }
+
+func resourceCloudStackVolumeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// Set the create-only arguments to their defaults, as they cannot be
+	// read back from the API.
+	d.Set("shrink_ok", false)
+	d.Set("upload_timeout", 3600)
+	return importStatePassthrough(d, meta)
+}
+
+// migrateVolume moves the volume to the given primary storage pool and
+// verifies the volume ended up on that pool once the async job finished.
+func migrateVolume(cs *cloudstack.CloudStackClient, d *schema.ResourceData, storageid string, live bool) error {
+	// Create a new parameter struct
+	p := cs.Volume.NewMigrateVolumeParams(storageid, d.Id())
+	p.SetLivemigrate(live)
+
+	log.Printf("[DEBUG] Migrating volume %s to storage pool %s", d.Id(), storageid)
+	if _, err := cs.Volume.MigrateVolume(p); err != nil {
+		return err
+	}
+
+	v, _, err := cs.Volume.GetVolumeByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if v.Storageid != storageid {
+		return fmt.Errorf(
+			"volume is on storage pool %s instead of %s after migration", v.Storageid, storageid)
+	}
+
+	return nil
+}
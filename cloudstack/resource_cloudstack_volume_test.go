@@ -0,0 +1,195 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackVolume_basic(t *testing.T) {
+	var volume cloudstack.Volume
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVolume_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVolumeExists(
+						"cloudstack_volume.foo", &volume),
+					resource.TestCheckResourceAttr(
+						"cloudstack_volume.foo", "name", "terraform-volume"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackVolume_update(t *testing.T) {
+	var volume cloudstack.Volume
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVolume_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVolumeExists(
+						"cloudstack_volume.foo", &volume),
+				),
+			},
+
+			{
+				Config: testAccCloudStackVolume_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVolumeExists(
+						"cloudstack_volume.foo", &volume),
+					resource.TestCheckResourceAttr(
+						"cloudstack_volume.foo", "name", "terraform-volume-updated"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_volume.foo", "delete_protection", "false"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_volume.foo", "tags.terraform-tag", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackVolume_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVolume_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_volume.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackVolumeExists(
+	n string, volume *cloudstack.Volume) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No volume ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		v, _, err := cs.Volume.GetVolumeByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if v.Id != rs.Primary.ID {
+			return fmt.Errorf("Volume not found")
+		}
+
+		*volume = *v
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackVolumeDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_volume" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No volume ID is set")
+		}
+
+		_, _, err := cs.Volume.GetVolumeByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Volume %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackVolume_basic = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_disk_offering" "foo" {
+  name         = "terraform-volume-offering"
+  display_text = "terraform-volume-offering"
+  disk_size    = 1
+}
+
+resource "cloudstack_volume" "foo" {
+  name             = "terraform-volume"
+  disk_offering_id = cloudstack_disk_offering.foo.id
+  zone_id          = data.cloudstack_zone.zone.id
+}`
+
+const testAccCloudStackVolume_update = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name  = "name"
+    value = "Sandbox-simulator"
+  }
+}
+
+resource "cloudstack_disk_offering" "foo" {
+  name         = "terraform-volume-offering"
+  display_text = "terraform-volume-offering"
+  disk_size    = 1
+}
+
+resource "cloudstack_volume" "foo" {
+  name              = "terraform-volume-updated"
+  disk_offering_id  = cloudstack_disk_offering.foo.id
+  zone_id           = data.cloudstack_zone.zone.id
+  delete_protection = false
+  tags = {
+    terraform-tag = "true"
+  }
+}`
@@ -20,8 +20,10 @@
 package cloudstack
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"strings"
 
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -29,7 +31,7 @@ import (
 )
 
 func resourceCloudStackVPC() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceCloudStackVPCCreate,
 		Read:   resourceCloudStackVPCRead,
 		Update: resourceCloudStackVPCUpdate,
@@ -38,6 +40,10 @@ func resourceCloudStackVPC() *schema.Resource {
 			State: importStatePassthrough,
 		},
 
+		Timeouts: restartTimeouts(),
+
+		CustomizeDiff: resourceCloudStackVPCCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -53,20 +59,23 @@ func resourceCloudStackVPC() *schema.Resource {
 			"cidr": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 
 			"vpc_offering": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 
 			"network_domain": {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
-				ForceNew: true,
+			},
+
+			"public_mtu": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
 			},
 
 			"project": {
@@ -90,6 +99,12 @@ func resourceCloudStackVPC() *schema.Resource {
 			"tags": tagsSchema(),
 		},
 	}
+
+	for k, v := range restartSchema() {
+		r.Schema[k] = v
+	}
+
+	return r
 }
 
 func resourceCloudStackVPCCreate(d *schema.ResourceData, meta interface{}) error {
@@ -130,6 +145,10 @@ func resourceCloudStackVPCCreate(d *schema.ResourceData, meta interface{}) error
 		p.SetNetworkdomain(networkDomain.(string))
 	}
 
+	if publicMTU, ok := d.GetOk("public_mtu"); ok {
+		p.SetPublicmtu(publicMTU.(int))
+	}
+
 	// If there is a project supplied, we retrieve and set the project id
 	if err := setProjectid(p, cs, d); err != nil {
 		return err
@@ -175,6 +194,7 @@ func resourceCloudStackVPCRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("display_text", v.Displaytext)
 	d.Set("cidr", v.Cidr)
 	d.Set("network_domain", v.Networkdomain)
+	d.Set("public_mtu", v.Publicmtu)
 
 	tags := make(map[string]interface{})
 	for _, tag := range v.Tags {
@@ -258,6 +278,65 @@ func resourceCloudStackVPCUpdate(d *schema.ResourceData, meta interface{}) error
 		}
 	}
 
+	// Check if the VPC offering is changed
+	if d.HasChange("vpc_offering") {
+		// Retrieve the vpc_offering ID
+		vpcofferingid, e := retrieveID(cs, "vpc_offering", d.Get("vpc_offering").(string))
+		if e != nil {
+			return e.Error()
+		}
+
+		// Create a new parameter struct
+		p := cs.VPC.NewUpdateVPCParams(d.Id())
+
+		// Set the new VPC offering
+		p.SetVpcofferingid(vpcofferingid)
+
+		// Update the VPC
+		_, err := cs.VPC.UpdateVPC(p)
+		if err != nil {
+			return fmt.Errorf(
+				"Error updating VPC offering of VPC %s: %s", name, err)
+		}
+	}
+
+	// Check if the CIDR is changed, which is only allowed when it is expanded
+	if d.HasChange("cidr") {
+		// Create a new parameter struct
+		p := cs.VPC.NewUpdateVPCParams(d.Id())
+
+		// Set the new CIDR
+		p.SetCidr(d.Get("cidr").(string))
+
+		// Update the VPC
+		_, err := cs.VPC.UpdateVPC(p)
+		if err != nil {
+			return fmt.Errorf(
+				"Error updating CIDR of VPC %s: %s", name, err)
+		}
+	}
+
+	// Check if the network domain or public MTU is changed
+	if d.HasChanges("network_domain", "public_mtu") {
+		// Create a new parameter struct
+		p := cs.VPC.NewUpdateVPCParams(d.Id())
+
+		if d.HasChange("network_domain") {
+			p.SetNetworkdomain(d.Get("network_domain").(string))
+		}
+
+		if d.HasChange("public_mtu") {
+			p.SetPublicmtu(d.Get("public_mtu").(int))
+		}
+
+		// Update the VPC
+		_, err := cs.VPC.UpdateVPC(p)
+		if err != nil {
+			return fmt.Errorf(
+				"Error updating network settings of VPC %s: %s", name, err)
+		}
+	}
+
 	// Check is the tags have changed
 	if d.HasChange("tags") {
 		err := updateTags(cs, d, "Vpc")
@@ -266,9 +345,60 @@ func resourceCloudStackVPCUpdate(d *schema.ResourceData, meta interface{}) error
 		}
 	}
 
+	// Restart the VPC if the restart trigger has changed
+	if d.HasChange("restart_trigger") {
+		if err := restartVPC(cs, d); err != nil {
+			return err
+		}
+	}
+
 	return resourceCloudStackVPCRead(d, meta)
 }
 
+func resourceCloudStackVPCCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("cidr") {
+		return nil
+	}
+
+	o, n := d.GetChange("cidr")
+
+	// The new CIDR may not be known yet during plan
+	if n.(string) == "" {
+		return nil
+	}
+
+	expanded, err := isCIDRExpansion(o.(string), n.(string))
+	if err != nil {
+		return err
+	}
+
+	// A VPC can only be expanded in place, any other change of the
+	// CIDR requires a new VPC
+	if !expanded {
+		return d.ForceNew("cidr")
+	}
+
+	return nil
+}
+
+// isCIDRExpansion returns true if the new CIDR contains the complete old CIDR.
+func isCIDRExpansion(oldCIDR, newCIDR string) (bool, error) {
+	_, o, err := net.ParseCIDR(oldCIDR)
+	if err != nil {
+		return false, fmt.Errorf("Unable to parse cidr %s: %s", oldCIDR, err)
+	}
+
+	_, n, err := net.ParseCIDR(newCIDR)
+	if err != nil {
+		return false, fmt.Errorf("Unable to parse cidr %s: %s", newCIDR, err)
+	}
+
+	oldOnes, _ := o.Mask.Size()
+	newOnes, _ := n.Mask.Size()
+
+	return n.Contains(o.IP) && newOnes <= oldOnes, nil
+}
+
 func resourceCloudStackVPCDelete(d *schema.ResourceData, meta interface{}) error {
 	cs := meta.(*cloudstack.CloudStackClient)
 
@@ -51,6 +51,75 @@ func TestAccCloudStackVPC_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackVPC_update(t *testing.T) {
+	var vpc cloudstack.VPC
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVPCDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVPC_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVPCExists(
+						"cloudstack_vpc.foo", &vpc),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpc.foo", "cidr", "10.0.0.0/16"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackVPC_updated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVPCNotRecreated("cloudstack_vpc.foo", &vpc),
+					testAccCheckCloudStackVPCExists(
+						"cloudstack_vpc.foo", &vpc),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpc.foo", "cidr", "10.0.0.0/8"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpc.foo", "vpc_offering", "Redundant VPC offering"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpc.foo", "network_domain", "terraform-domain-updated"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpc.foo", "public_mtu", "1450"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackVPC_restart(t *testing.T) {
+	var vpc cloudstack.VPC
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVPCDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVPC_restart("1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVPCExists(
+						"cloudstack_vpc.foo", &vpc),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpc.foo", "restart_trigger", "1"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackVPC_restart("2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVPCExists(
+						"cloudstack_vpc.foo", &vpc),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpc.foo", "restart_trigger", "2"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackVPC_import(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -123,6 +192,22 @@ func testAccCheckCloudStackVPCAttributes(
 	}
 }
 
+func testAccCheckCloudStackVPCNotRecreated(
+	n string, vpc *cloudstack.VPC) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID != vpc.Id {
+			return fmt.Errorf("VPC was recreated: %s != %s", rs.Primary.ID, vpc.Id)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckCloudStackVPCDestroy(s *terraform.State) error {
 	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
 
@@ -156,3 +241,34 @@ resource "cloudstack_vpc" "foo" {
     terraform-tag = "true"
   }
 }`
+
+func testAccCloudStackVPC_restart(trigger string) string {
+	return fmt.Sprintf(`
+resource "cloudstack_vpc" "foo" {
+  name = "terraform-vpc"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+  restart_trigger = "%s"
+  restart_cleanup = true
+}`, trigger)
+}
+
+const testAccCloudStackVPC_update = `
+resource "cloudstack_vpc" "foo" {
+  name = "terraform-vpc"
+  cidr = "10.0.0.0/16"
+  vpc_offering = "Default VPC offering"
+  network_domain = "terraform-domain"
+  zone = "Sandbox-simulator"
+}`
+
+const testAccCloudStackVPC_updated = `
+resource "cloudstack_vpc" "foo" {
+  name = "terraform-vpc"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Redundant VPC offering"
+  network_domain = "terraform-domain-updated"
+  public_mtu = 1450
+  zone = "Sandbox-simulator"
+}`
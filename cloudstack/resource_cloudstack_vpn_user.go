@@ -0,0 +1,186 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudStackVPNUser() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackVPNUserCreate,
+		Read:   resourceCloudStackVPNUserRead,
+		Update: resourceCloudStackVPNUserUpdate,
+		Delete: resourceCloudStackVPNUserDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+		// Changing the password creates a new user, unless it is unknown
+		// because the user was imported
+		CustomizeDiff: forceNewUnlessUnset("password"),
+
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"password": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+
+			"account": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"domain_id"},
+			},
+
+			"domain_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"project": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"account"},
+			},
+
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackVPNUserCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	username := d.Get("username").(string)
+
+	// Create a new parameter struct
+	p := cs.VPN.NewAddVpnUserParams(d.Get("password").(string), username)
+
+	if account, ok := d.GetOk("account"); ok {
+		p.SetAccount(account.(string))
+	}
+
+	if domainid, ok := d.GetOk("domain_id"); ok {
+		p.SetDomainid(domainid.(string))
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	// Add the VPN user
+	u, err := cs.VPN.AddVpnUser(p)
+	if err != nil {
+		return fmt.Errorf("Error adding VPN user %s: %s", username, err)
+	}
+
+	d.SetId(u.Id)
+
+	return resourceCloudStackVPNUserRead(d, meta)
+}
+
+func resourceCloudStackVPNUserRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	// Get the VPN user details
+	u, count, err := cs.VPN.GetVpnUserByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		if count == 0 {
+			log.Printf("[DEBUG] VPN user %s does no longer exist", d.Get("username").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("username", u.Username)
+	d.Set("account", u.Account)
+	d.Set("domain_id", u.Domainid)
+	d.Set("state", u.State)
+
+	setValueOrID(d, "project", u.Project, u.Projectid)
+
+	return nil
+}
+
+func resourceCloudStackVPNUserUpdate(d *schema.ResourceData, meta interface{}) error {
+	// The only in place update stores the password of an imported user,
+	// which is not sent to CloudStack
+	return resourceCloudStackVPNUserRead(d, meta)
+}
+
+func resourceCloudStackVPNUserDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*cloudstack.CloudStackClient)
+
+	username := d.Get("username").(string)
+
+	// Create a new parameter struct
+	p := cs.VPN.NewRemoveVpnUserParams(username)
+
+	// Users of a project are removed through the project, others through their account
+	if project, ok := d.GetOk("project"); ok {
+		projectid, e := retrieveID(cs, "project", project.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetProjectid(projectid)
+	} else if account, ok := d.GetOk("account"); ok {
+		p.SetAccount(account.(string))
+		p.SetDomainid(d.Get("domain_id").(string))
+	}
+
+	// Remove the VPN user
+	_, err := cs.VPN.RemoveVpnUser(p)
+	if err != nil {
+		// This is a very poor way to be told the user does no longer exist :(
+		if strings.Contains(err.Error(), "Unable to find VPN user") {
+			return nil
+		}
+
+		return fmt.Errorf("Error removing VPN user %s: %s", username, err)
+	}
+
+	return nil
+}
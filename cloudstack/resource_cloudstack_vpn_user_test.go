@@ -0,0 +1,137 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCloudStackVPNUser_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVPNUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVPNUser_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVPNUserExists("cloudstack_vpn_user.foo"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpn_user.foo", "username", "terraform-vpn-user"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackVPNUser_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVPNUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVPNUser_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_vpn_user.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The password can't be read back from the CloudStack API
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+
+			{
+				ResourceName:       "cloudstack_vpn_user.foo",
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+
+			{
+				// The unknown password of an imported user is stored in place
+				Config: testAccCloudStackVPNUser_basic,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("cloudstack_vpn_user.foo", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackVPNUserExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No VPN user ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		u, _, err := cs.VPN.GetVpnUserByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if u.Id != rs.Primary.ID {
+			return fmt.Errorf("VPN user not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackVPNUserDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_vpn_user" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No VPN user ID is set")
+		}
+
+		_, _, err := cs.VPN.GetVpnUserByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("VPN user %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackVPNUser_basic = `
+resource "cloudstack_vpn_user" "foo" {
+  username = "terraform-vpn-user"
+  password = "terraform-Passw0rd"
+}`
@@ -24,6 +24,8 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -82,6 +84,8 @@ func retrieveID(cs *cloudstack.CloudStackClient, name string, value string, opts
 		id, _, err = cs.Project.GetProjectID(value)
 	case "service_offering":
 		id, _, err = cs.ServiceOffering.GetServiceOfferingID(value)
+	case "storage_pool":
+		id, _, err = cs.Pool.GetStoragePoolID(value)
 	case "vpc_offering":
 		id, _, err = cs.VPC.GetVPCOfferingID(value)
 	case "zone":
@@ -187,6 +191,111 @@ func setProjectid(p cloudstack.ProjectIDSetter, cs *cloudstack.CloudStackClient,
 	return nil
 }
 
+// formatPortRange returns a port range in the "80" or "80-90" notation used
+// by the ports attribute of the firewall and security group rule resources.
+func formatPortRange(startPort, endPort int) string {
+	if endPort == 0 || endPort == startPort {
+		return strconv.Itoa(startPort)
+	}
+	return fmt.Sprintf("%d-%d", startPort, endPort)
+}
+
+// ruleError adds the protocol and CIDR list of a rule to an error, so the
+// errors of multiple rules can be told apart when they are reported together.
+func ruleError(action string, rule map[string]interface{}, err error) error {
+	desc := rule["protocol"].(string)
+	if cidrs := setToStrings(rule["cidr_list"]); len(cidrs) > 0 {
+		desc += " from " + strings.Join(cidrs, ", ")
+	}
+	return fmt.Errorf("Error %s %s rule: %s", action, desc, err)
+}
+
+// sortedCIDRList returns a comma-separated CIDR list in sorted order, so
+// rules using the same CIDRs in a different order can be grouped together.
+func sortedCIDRList(list string) string {
+	cidrs := strings.Split(list, ",")
+	sort.Strings(cidrs)
+	return strings.Join(cidrs, ",")
+}
+
+// importedRules groups imported rules into rule blocks. Create splits a rule
+// with multiple ports into one rule per port, so all imported rules with the
+// same key are grouped back into a single rule block.
+type importedRules struct {
+	rules map[string]map[string]interface{}
+	keys  []string
+}
+
+func newImportedRules() *importedRules {
+	return &importedRules{rules: make(map[string]map[string]interface{})}
+}
+
+// group returns the rule block for the given key, creating it with newRule
+// and empty ports and uuids if there is none yet.
+func (g *importedRules) group(key string, newRule func() map[string]interface{}) map[string]interface{} {
+	rule, ok := g.rules[key]
+	if !ok {
+		rule = newRule()
+		rule["ports"] = &schema.Set{F: schema.HashString}
+		rule["uuids"] = make(map[string]interface{})
+
+		g.rules[key] = rule
+		g.keys = append(g.keys, key)
+	}
+	return rule
+}
+
+// addPort adds a port range to a rule block and stores the ID of the rule
+// using that port range in its uuids, prefixed with the given prefix.
+func (g *importedRules) addPort(rule map[string]interface{}, prefix string, startPort, endPort int, id string) {
+	port := formatPortRange(startPort, endPort)
+	rule["ports"].(*schema.Set).Add(port)
+	rule["uuids"].(map[string]interface{})[prefix+port] = id
+}
+
+// list returns the rule blocks in the order they were created.
+func (g *importedRules) list() []map[string]interface{} {
+	rules := make([]map[string]interface{}, 0, len(g.keys))
+	for _, key := range g.keys {
+		rules = append(rules, g.rules[key])
+	}
+	return rules
+}
+
+// forceNewUnlessUnset returns a CustomizeDiffFunc that forces a new resource
+// when one of the given attributes changes, unless the attribute has no value
+// in the state. This is used for attributes that are not returned by the API,
+// which have no value after an import. The first apply after an import then
+// only stores the configured value in place, so any later change of the
+// attribute shows up in the plan and forces a new resource again.
+func forceNewUnlessUnset(keys ...string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		for _, key := range keys {
+			if !d.HasChange(key) {
+				continue
+			}
+
+			old, _ := d.GetChange(key)
+			switch v := old.(type) {
+			case string:
+				if v == "" {
+					continue
+				}
+			case int:
+				if v == 0 {
+					continue
+				}
+			}
+
+			if err := d.ForceNew(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
 // importStatePassthrough is a generic importer with project support.
 func importStatePassthrough(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// Try to split the ID to extract the optional project name.
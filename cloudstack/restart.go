@@ -0,0 +1,147 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// restartMutex serializes network and VPC restarts, so restarting several
+// networks or VPCs in the same apply doesn't take down all routers at once.
+var restartMutex sync.Mutex
+
+// restartTimeout is the default time to wait for the routers to come back
+// after a network or VPC restart. It can be changed with the update timeout
+// of the resource.
+const restartTimeout = 20 * time.Minute
+
+// restartTimeouts returns the timeouts of a resource that can be restarted.
+func restartTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Update: schema.DefaultTimeout(restartTimeout),
+	}
+}
+
+// restartSchema returns the schema of the attributes used to restart a
+// network or VPC, which are shared by both resources.
+func restartSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"restart_trigger": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+
+		"restart_cleanup": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+
+		"restart_make_redundant": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+
+		"restart_livepatch": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+	}
+}
+
+// restartNetwork restarts a network using the configured restart options and
+// waits for its routers to be running again.
+func restartNetwork(cs *cloudstack.CloudStackClient, d *schema.ResourceData) error {
+	restartMutex.Lock()
+	defer restartMutex.Unlock()
+
+	p := cs.Network.NewRestartNetworkParams(d.Id())
+	p.SetCleanup(d.Get("restart_cleanup").(bool))
+	p.SetMakeredundant(d.Get("restart_make_redundant").(bool))
+	p.SetLivepatch(d.Get("restart_livepatch").(bool))
+
+	log.Printf("[DEBUG] Restarting network %s", d.Id())
+
+	if _, err := cs.Network.RestartNetwork(p); err != nil {
+		return fmt.Errorf("Error restarting network %s: %s", d.Get("name").(string), err)
+	}
+
+	lp := cs.Router.NewListRoutersParams()
+	lp.SetNetworkid(d.Id())
+
+	return waitForRouters(cs, d, lp)
+}
+
+// restartVPC restarts a VPC using the configured restart options and waits
+// for its routers to be running again.
+func restartVPC(cs *cloudstack.CloudStackClient, d *schema.ResourceData) error {
+	restartMutex.Lock()
+	defer restartMutex.Unlock()
+
+	p := cs.VPC.NewRestartVPCParams(d.Id())
+	p.SetCleanup(d.Get("restart_cleanup").(bool))
+	p.SetMakeredundant(d.Get("restart_make_redundant").(bool))
+	p.SetLivepatch(d.Get("restart_livepatch").(bool))
+
+	log.Printf("[DEBUG] Restarting VPC %s", d.Id())
+
+	if _, err := cs.VPC.RestartVPC(p); err != nil {
+		return fmt.Errorf("Error restarting VPC %s: %s", d.Get("name").(string), err)
+	}
+
+	lp := cs.Router.NewListRoutersParams()
+	lp.SetVpcid(d.Id())
+
+	return waitForRouters(cs, d, lp)
+}
+
+// waitForRouters waits until all routers matching the given parameters are
+// running.
+func waitForRouters(cs *cloudstack.CloudStackClient, d *schema.ResourceData, p *cloudstack.ListRoutersParams) error {
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	return retry.RetryContext(context.Background(), d.Timeout(schema.TimeoutUpdate), func() *retry.RetryError {
+		l, err := cs.Router.ListRouters(p)
+		if err != nil {
+			return retry.NonRetryableError(
+				fmt.Errorf("Error listing routers of %s: %s", d.Id(), err))
+		}
+
+		for _, r := range l.Routers {
+			if r.State != "Running" {
+				log.Printf("[DEBUG] Router %s of %s is %s, waiting", r.Name, d.Id(), r.State)
+				return retry.RetryableError(
+					fmt.Errorf("Router %s of %s is %s", r.Name, d.Id(), r.State))
+			}
+		}
+
+		return nil
+	})
+}
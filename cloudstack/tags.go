@@ -119,3 +119,60 @@ func tagsToMap(tags []cloudstack.Tags) map[string]string {
 	}
 	return result
 }
+
+// ruleTags returns the tags of a rule block, including its description which
+// is stored as the "description" tag.
+func ruleTags(rule map[string]interface{}) map[string]string {
+	tags := make(map[string]string)
+	if t, ok := rule["tags"].(map[string]interface{}); ok {
+		tags = tagsFromSchema(t)
+	}
+	if description, ok := rule["description"].(string); ok && description != "" {
+		tags["description"] = description
+	}
+	return tags
+}
+
+// readRuleTags sets the description and tags of a rule block from the tags
+// of one of its rules.
+func readRuleTags(rule map[string]interface{}, tags []cloudstack.Tags) {
+	t := make(map[string]interface{})
+	rule["description"] = ""
+	for k, v := range tagsToMap(tags) {
+		if k == "description" {
+			rule["description"] = v
+			continue
+		}
+		t[k] = v
+	}
+	rule["tags"] = t
+}
+
+// updateRuleTags updates the tags of the given rules from the description and
+// tags of the old rule block to those of the new rule block.
+func updateRuleTags(cs *cloudstack.CloudStackClient, ids []string, resourcetype string, o, n map[string]interface{}) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	remove, create := diffTags(ruleTags(o), ruleTags(n))
+
+	// First remove any obsolete tags
+	if len(remove) > 0 {
+		p := cs.Resourcetags.NewDeleteTagsParams(ids, resourcetype)
+		p.SetTags(remove)
+		if _, err := cs.Resourcetags.DeleteTags(p); err != nil {
+			return err
+		}
+	}
+
+	// Then add any new tags
+	if len(create) > 0 {
+		p := cs.Resourcetags.NewCreateTagsParams(ids, resourcetype, create)
+		if _, err := cs.Resourcetags.CreateTags(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
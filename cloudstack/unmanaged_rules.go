@@ -0,0 +1,290 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	managedTrue   = "true"
+	managedFalse  = "false"
+	managedReport = "report"
+)
+
+// managedSchema returns the schema of the managed field of the rule resources.
+// When "true" all unknown rules are deleted, when "report" they are only
+// reported in unmanaged_rules.
+func managedSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      managedFalse,
+		ValidateFunc: validation.StringInSlice([]string{managedTrue, managedFalse, managedReport}, false),
+	}
+}
+
+// unmanagedRulesSchema returns the schema of the rules that are found when
+// managed is "report", but are not part of the configuration.
+func unmanagedRulesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"uuid": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+
+				"rule_number": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+
+				"action": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+
+				"traffic_type": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+
+				"protocol": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+
+				"cidr_list": {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+
+				"port": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+
+				"icmp_type": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+
+				"icmp_code": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+
+				"description": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+// managedStateUpgradeV0 upgrades the state of a rule resource of which the
+// managed field was a bool, before it also accepted "report".
+func managedStateUpgradeV0(r *schema.Resource) schema.StateUpgrader {
+	v0 := make(map[string]*schema.Schema, len(r.Schema))
+	for k, s := range r.Schema {
+		v0[k] = s
+	}
+	delete(v0, "unmanaged_rules")
+	v0["managed"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+
+	return schema.StateUpgrader{
+		Version: 0,
+		Type:    (&schema.Resource{Schema: v0}).CoreConfigSchema().ImpliedType(),
+		Upgrade: func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			if managed, ok := rawState["managed"].(bool); ok {
+				rawState["managed"] = strconv.FormatBool(managed)
+			}
+			return rawState, nil
+		},
+	}
+}
+
+// readReportingUnmanagedRules wraps the read function of a rule resource and
+// returns a warning for every rule in unmanaged_rules, so unmanaged rules are
+// reported when planning.
+func readReportingUnmanagedRules(read schema.ReadFunc) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if err := read(d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if d.Id() == "" || d.Get("managed").(string) != managedReport {
+			return nil
+		}
+
+		var diags diag.Diagnostics
+		for _, rule := range d.Get("unmanaged_rules").([]interface{}) {
+			rule := rule.(map[string]interface{})
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Unmanaged rule %s found on %s", rule["uuid"], d.Id()),
+				Detail: fmt.Sprintf(
+					"The rule (%s) is not part of the configuration. As managed is set to %q, "+
+						"it is only reported and will not be deleted.", describeUnmanagedRule(rule), managedReport),
+			})
+		}
+
+		return diags
+	}
+}
+
+// setUnmanagedRules sets the rules of which the UUIDs are not known, sorted
+// by rule number and UUID. The rules are only set when managed is "report".
+func setUnmanagedRules(d *schema.ResourceData, rules []map[string]interface{}) error {
+	if d.Get("managed").(string) != managedReport {
+		rules = nil
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		ni, _ := rules[i]["rule_number"].(int)
+		nj, _ := rules[j]["rule_number"].(int)
+		if ni != nj {
+			return ni < nj
+		}
+		return rules[i]["uuid"].(string) < rules[j]["uuid"].(string)
+	})
+
+	list := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		list = append(list, rule)
+	}
+
+	return d.Set("unmanaged_rules", list)
+}
+
+// firewallUnmanagedRule returns an unmanaged rule for a firewall rule.
+func firewallUnmanagedRule(r *cloudstack.FirewallRule) map[string]interface{} {
+	rule := map[string]interface{}{
+		"uuid":         r.Id,
+		"traffic_type": "ingress",
+		"protocol":     r.Protocol,
+		"cidr_list":    splitCIDRList(r.Cidrlist),
+	}
+
+	switch r.Protocol {
+	case "icmp":
+		rule["icmp_type"] = r.Icmptype
+		rule["icmp_code"] = r.Icmpcode
+	case "tcp", "udp":
+		if r.Startport != 0 {
+			rule["port"] = formatPortRange(r.Startport, r.Endport)
+		}
+	}
+
+	return rule
+}
+
+// ipv6FirewallUnmanagedRule returns an unmanaged rule for an IPv6 firewall rule.
+func ipv6FirewallUnmanagedRule(r *cloudstack.Ipv6FirewallRule) map[string]interface{} {
+	rule := map[string]interface{}{
+		"uuid":         r.Id,
+		"traffic_type": strings.ToLower(r.Traffictype),
+		"protocol":     r.Protocol,
+		"cidr_list":    splitCIDRList(r.Cidrlist),
+	}
+
+	switch strings.ToLower(r.Protocol) {
+	case "icmp":
+		rule["icmp_type"] = r.Icmptype
+		rule["icmp_code"] = r.Icmpcode
+	case "tcp", "udp":
+		if r.Startport != 0 {
+			rule["port"] = formatPortRange(r.Startport, r.Endport)
+		}
+	}
+
+	return rule
+}
+
+// aclUnmanagedRule returns an unmanaged rule for a network ACL rule.
+func aclUnmanagedRule(r *cloudstack.NetworkACL) map[string]interface{} {
+	rule := buildRuleFromAPI(r)
+	rule["cidr_list"] = splitCIDRList(r.Cidrlist)
+	return rule
+}
+
+func splitCIDRList(list string) []interface{} {
+	var cidrs []interface{}
+	for _, cidr := range strings.Split(list, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+// describeUnmanagedRule returns a short description of an unmanaged rule,
+// e.g. "ingress allow tcp port 22 from 0.0.0.0/0".
+func describeUnmanagedRule(rule map[string]interface{}) string {
+	var parts []string
+	for _, k := range []string{"traffic_type", "action", "protocol"} {
+		if v, _ := rule[k].(string); v != "" {
+			parts = append(parts, v)
+		}
+	}
+
+	switch rule["protocol"] {
+	case "icmp":
+		parts = append(parts, fmt.Sprintf("type %v code %v", rule["icmp_type"], rule["icmp_code"]))
+	case "tcp", "udp":
+		if port, _ := rule["port"].(string); port != "" {
+			parts = append(parts, "port "+port)
+		} else {
+			parts = append(parts, "all ports")
+		}
+	}
+
+	var cidrs []string
+	for _, cidr := range rule["cidr_list"].([]interface{}) {
+		cidrs = append(cidrs, cidr.(string))
+	}
+	if len(cidrs) > 0 {
+		parts = append(parts, "from "+strings.Join(cidrs, ", "))
+	}
+
+	if number, _ := rule["rule_number"].(int); number != 0 {
+		parts = append([]string{fmt.Sprintf("#%d", number)}, parts...)
+	}
+
+	return strings.Join(parts, " ")
+}